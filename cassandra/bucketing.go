@@ -0,0 +1,249 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	cqlx "github.com/scylladb/gocqlx"
+	"github.com/scylladb/gocqlx/reflectx"
+)
+
+// BucketUnit is the calendar-unit for time-buckets.
+type BucketUnit string
+
+// The calendar-units for time-buckets, and their bucket-values
+// (for time 2018-01-15 13:04:05).
+const (
+	// Bucket-value: 2018011513
+	BucketHour BucketUnit = "hour"
+	// Bucket-value: 20180115
+	BucketDay BucketUnit = "day"
+	// Bucket-value: 201801
+	BucketMonth BucketUnit = "month"
+	// Bucket-value: 2018
+	BucketYear BucketUnit = "year"
+)
+
+// Bucketing defines the time-bucketing of partitions, where a partition-key
+// column (such as "year_bucket") stores the time-bucket computed from a
+// timestamp column. The bucket is set on #AsyncInsert, and #SelectTimeRange
+// queries all buckets in a time-window.
+// Sample:
+//  &Bucketing{
+//    BucketColumn: "year_bucket",
+//    TimeColumn:   "timestamp",
+//    Unit:         BucketYear,
+//  }
+type Bucketing struct {
+	// Partition-key column storing the bucket. This must have an integer
+	// data-type large enough for bucket-values (int, or smallint for BucketYear).
+	BucketColumn string
+	// Timestamp column the bucket is computed from,
+	// this must be the first clustering-column
+	TimeColumn string
+	// Calendar-unit of buckets
+	Unit BucketUnit
+	// Fixed bucket-size, used instead of Unit. The bucket-value is the
+	// number of Size-intervals since Unix-epoch.
+	Size time.Duration
+	// Timezone for calendar-units, defaults to UTC
+	Location *time.Location
+}
+
+// Bucket returns the bucket-value for time.
+func (b *Bucketing) Bucket(ts time.Time) int64 {
+	if b.Size > 0 {
+		return floorDiv(ts.UnixNano(), int64(b.Size))
+	}
+	ts = ts.In(b.location())
+	year, month, day := int64(ts.Year()), int64(ts.Month()), int64(ts.Day())
+	switch b.Unit {
+	case BucketHour:
+		return year*1000000 + month*10000 + day*100 + int64(ts.Hour())
+	case BucketDay:
+		return year*10000 + month*100 + day
+	case BucketMonth:
+		return year*100 + month
+	}
+	return year
+}
+
+// Buckets returns the bucket-values for time-window from (inclusive)
+// to (exclusive), in ascending order.
+func (b *Bucketing) Buckets(from time.Time, to time.Time) []int64 {
+	buckets := []int64{}
+	for start := b.start(from); start.Before(to); start = b.next(start) {
+		buckets = append(buckets, b.Bucket(start))
+	}
+	return buckets
+}
+
+// start returns the start-time of bucket containing time.
+func (b *Bucketing) start(ts time.Time) time.Time {
+	if b.Size > 0 {
+		return time.Unix(0, floorDiv(ts.UnixNano(), int64(b.Size))*int64(b.Size))
+	}
+	ts = ts.In(b.location())
+	year, month, day := ts.Date()
+	switch b.Unit {
+	case BucketHour:
+		return time.Date(year, month, day, ts.Hour(), 0, 0, 0, b.location())
+	case BucketDay:
+		return time.Date(year, month, day, 0, 0, 0, 0, b.location())
+	case BucketMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, b.location())
+	}
+	return time.Date(year, 1, 1, 0, 0, 0, 0, b.location())
+}
+
+// next returns the start-time of bucket following the bucket starting at start.
+func (b *Bucketing) next(start time.Time) time.Time {
+	if b.Size > 0 {
+		return start.Add(b.Size)
+	}
+	year, month, day := start.Date()
+	switch b.Unit {
+	case BucketHour:
+		return time.Date(year, month, day, start.Hour()+1, 0, 0, 0, b.location())
+	case BucketDay:
+		return time.Date(year, month, day+1, 0, 0, 0, 0, b.location())
+	case BucketMonth:
+		return time.Date(year, month+1, 1, 0, 0, 0, 0, b.location())
+	}
+	return time.Date(year+1, 1, 1, 0, 0, 0, 0, b.location())
+}
+
+func (b *Bucketing) location() *time.Location {
+	if b.Location == nil {
+		return time.UTC
+	}
+	return b.Location
+}
+
+// validate checks the bucketing against table-definition.
+func (b *Bucketing) validate(t *Table) error {
+	if b == nil {
+		return nil
+	}
+	if b.Size < 0 {
+		return errors.New("Bucketing Size cannot be negative")
+	}
+	if b.Size == 0 {
+		switch b.Unit {
+		case BucketHour, BucketDay, BucketMonth, BucketYear:
+		default:
+			return fmt.Errorf("Invalid bucketing Unit: \"%s\"", b.Unit)
+		}
+	}
+	if !isPrimaryKey(t.PartitionKeyColumns(), b.BucketColumn) {
+		return fmt.Errorf(
+			"Bucketing BucketColumn must be a partition-key column. Errored Key: \"%s\"",
+			b.BucketColumn,
+		)
+	}
+	// The time-range restrictions of #SelectTimeRange
+	// require the first clustering-column
+	clusteringColumns := t.ClusteringColumns()
+	if len(clusteringColumns) == 0 || clusteringColumns[0].Name != b.TimeColumn {
+		return fmt.Errorf(
+			"Bucketing TimeColumn must be the first clustering-column. Errored Key: \"%s\"",
+			b.TimeColumn,
+		)
+	}
+	return nil
+}
+
+// setBucket sets the bucket-column field of dataStruct (pointer to struct),
+// from its time-column field.
+func (b *Bucketing) setBucket(dataStruct interface{}) error {
+	v := reflect.ValueOf(dataStruct)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errors.New("Bucketing requires data to be a pointer to struct")
+	}
+
+	ts, isTime := rowValue(v, b.TimeColumn).(time.Time)
+	if !isTime {
+		return fmt.Errorf(
+			"Bucketing requires a time.Time field for column %s", b.TimeColumn,
+		)
+	}
+	bucket := b.Bucket(ts)
+
+	fi, exists := cqlx.DefaultMapper.TypeMap(v.Elem().Type()).Names[b.BucketColumn]
+	if !exists {
+		return fmt.Errorf("No field found for column %s", b.BucketColumn)
+	}
+	field := reflectx.FieldByIndexes(v.Elem(), fi.Index)
+	if !field.CanSet() {
+		return fmt.Errorf("No field found for column %s", b.BucketColumn)
+	}
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !field.OverflowInt(bucket) {
+			field.SetInt(bucket)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if bucket >= 0 && !field.OverflowUint(uint64(bucket)) {
+			field.SetUint(uint64(bucket))
+			return nil
+		}
+	default:
+		return fmt.Errorf(
+			"Field for column %s must be an integer, found: %s",
+			b.BucketColumn, field.Kind(),
+		)
+	}
+	return fmt.Errorf("Bucket %d overflows field for column %s", bucket, b.BucketColumn)
+}
+
+// SelectTimeRange gets data from all time-buckets in time-window from
+// (inclusive) to (exclusive), using #SelectPartitions. The partition has
+// values for partition-key columns other than the bucket-column, and can
+// be nil if the bucket-column is the only partition-key column.
+// The time-column is restricted to time-window, in addition to
+// SelectParams.ColumnValues.
+func (t *Table) SelectTimeRange(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+	partition Partition,
+	p SelectParams,
+) (interface{}, error) {
+	if t.bucketing == nil {
+		return nil, errors.New("SelectTimeRange requires Bucketing in TableConfig")
+	}
+	if !from.Before(to) {
+		return nil, errors.New("SelectTimeRange requires from to be before to")
+	}
+	b := t.bucketing
+
+	partitions := []Partition{}
+	for _, bucket := range b.Buckets(from, to) {
+		bucketPartition := Partition{
+			b.BucketColumn: bucket,
+		}
+		for column, value := range partition {
+			bucketPartition[column] = value
+		}
+		partitions = append(partitions, bucketPartition)
+	}
+
+	p.ColumnValues = append([]ColumnComparator{
+		Comparator(b.TimeColumn, from).GtOrEq(),
+		Comparator(b.TimeColumn, to).Lt(),
+	}, p.ColumnValues...)
+	return t.SelectPartitions(ctx, partitions, p)
+}
+
+// floorDiv divides a by b (b > 0), rounding towards negative infinity.
+func floorDiv(a int64, b int64) int64 {
+	q := a / b
+	if a%b < 0 {
+		q--
+	}
+	return q
+}
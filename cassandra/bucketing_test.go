@@ -0,0 +1,235 @@
+package cassandra
+
+import (
+	"context"
+	"time"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bucketing", func() {
+	ts := time.Date(2018, 1, 15, 13, 4, 5, 0, time.UTC)
+
+	It("should compute buckets by calendar-unit", func() {
+		Expect((&Bucketing{Unit: BucketHour}).Bucket(ts)).To(Equal(int64(2018011513)))
+		Expect((&Bucketing{Unit: BucketDay}).Bucket(ts)).To(Equal(int64(20180115)))
+		Expect((&Bucketing{Unit: BucketMonth}).Bucket(ts)).To(Equal(int64(201801)))
+		Expect((&Bucketing{Unit: BucketYear}).Bucket(ts)).To(Equal(int64(2018)))
+
+		loc := time.FixedZone("UTC+12", 12*60*60)
+		b := &Bucketing{Unit: BucketDay, Location: loc}
+		Expect(b.Bucket(ts)).To(Equal(int64(20180116)))
+	})
+
+	It("should compute fixed-size buckets", func() {
+		b := &Bucketing{Size: 10 * time.Minute}
+		Expect(b.Bucket(time.Unix(0, 0))).To(Equal(int64(0)))
+		Expect(b.Bucket(time.Unix(599, 0))).To(Equal(int64(0)))
+		Expect(b.Bucket(time.Unix(600, 0))).To(Equal(int64(1)))
+		Expect(b.Bucket(time.Unix(-1, 0))).To(Equal(int64(-1)))
+	})
+
+	It("should enumerate buckets in time-window", func() {
+		b := &Bucketing{Unit: BucketMonth}
+		Expect(b.Buckets(
+			time.Date(2017, 11, 20, 0, 0, 0, 0, time.UTC),
+			time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC),
+		)).To(Equal([]int64{201711, 201712, 201801}))
+
+		b = &Bucketing{Unit: BucketHour}
+		Expect(b.Buckets(ts, ts.Add(time.Hour))).To(Equal([]int64{2018011513, 2018011514}))
+
+		b = &Bucketing{Size: time.Hour}
+		Expect(b.Buckets(time.Unix(3599, 0), time.Unix(3601, 0))).To(Equal([]int64{0, 1}))
+	})
+
+	Context("table has bucketing", func() {
+		type event struct {
+			Tenant     string    `db:"tenant"`
+			YearBucket uint16    `db:"year_bucket"`
+			Timestamp  time.Time `db:"timestamp"`
+		}
+
+		var (
+			session    *mocks.Session
+			keyspace   *Keyspace
+			definition map[string]TableColumn
+		)
+
+		newTable := func(b *Bucketing) (*Table, error) {
+			table, err := NewTable(session, &TableConfig{
+				Keyspace:  keyspace,
+				Name:      "test_table",
+				Bucketing: b,
+			}, &definition)
+			if err != nil {
+				return nil, err
+			}
+			table.initQueryx = func(q driver.QueryI, names []string) driver.QueryxI {
+				return &mocks.Queryx{
+					CqlQuery:    q,
+					ColumnNames: names,
+				}
+			}
+			return table, nil
+		}
+
+		BeforeEach(func() {
+			session = &mocks.Session{}
+			var err error
+			keyspace, err = NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			definition = map[string]TableColumn{
+				"tenant": TableColumn{
+					Name:              "tenant",
+					DataType:          "text",
+					PrimaryKeyIndex:   "0",
+					PartitionKeyIndex: "0",
+				},
+				"yearBucket": TableColumn{
+					Name:              "year_bucket",
+					DataType:          "smallint",
+					PrimaryKeyIndex:   "0",
+					PartitionKeyIndex: "1",
+				},
+				"timestamp": TableColumn{
+					Name:            "timestamp",
+					DataType:        "timestamp",
+					PrimaryKeyIndex: "1",
+					PrimaryKeyOrder: "DESC",
+				},
+			}
+		})
+
+		It("should validate bucketing against table-definition", func() {
+			invalid := []*Bucketing{
+				&Bucketing{BucketColumn: "year_bucket", TimeColumn: "timestamp"},
+				&Bucketing{BucketColumn: "timestamp", TimeColumn: "timestamp", Unit: BucketYear},
+				&Bucketing{BucketColumn: "year_bucket", TimeColumn: "invalid", Unit: BucketYear},
+				&Bucketing{BucketColumn: "year_bucket", TimeColumn: "timestamp", Size: -1},
+			}
+			for _, b := range invalid {
+				_, err := newTable(b)
+				Expect(err).To(HaveOccurred())
+			}
+		})
+
+		It("should require TimeColumn to be the first clustering-column", func() {
+			definition["createdAt"] = TableColumn{
+				Name:     "created_at",
+				DataType: "timestamp",
+			}
+			_, err := newTable(&Bucketing{
+				BucketColumn: "year_bucket",
+				TimeColumn:   "created_at",
+				Unit:         BucketYear,
+			})
+			Expect(err).To(HaveOccurred())
+
+			definition["id"] = TableColumn{
+				Name:            "id",
+				DataType:        "int",
+				PrimaryKeyIndex: "1",
+			}
+			timestamp := definition["timestamp"]
+			timestamp.PrimaryKeyIndex = "2"
+			definition["timestamp"] = timestamp
+			_, err = newTable(&Bucketing{
+				BucketColumn: "year_bucket",
+				TimeColumn:   "timestamp",
+				Unit:         BucketYear,
+			})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should set the bucket on insert", func() {
+			table, err := newTable(&Bucketing{
+				BucketColumn: "year_bucket",
+				TimeColumn:   "timestamp",
+				Unit:         BucketYear,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(table.Bucketing().Unit).To(Equal(BucketYear))
+
+			e := &event{Tenant: "t1", Timestamp: ts}
+			errChan := table.AsyncInsert(e)
+			// Bucket is set before the insert runs in background
+			Expect(e.YearBucket).To(Equal(uint16(2018)))
+			Expect(<-errChan).To(Succeed())
+
+			Expect(<-table.AsyncInsert(event{})).To(HaveOccurred())
+		})
+
+		It("should return error if struct has no bucket-field", func() {
+			table, err := newTable(&Bucketing{
+				BucketColumn: "year_bucket",
+				TimeColumn:   "timestamp",
+				Unit:         BucketYear,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			e := &struct {
+				Timestamp time.Time `db:"timestamp"`
+			}{ts}
+			Expect(<-table.AsyncInsert(e)).To(HaveOccurred())
+		})
+
+		It("should return error if bucket overflows field", func() {
+			table, err := newTable(&Bucketing{
+				BucketColumn: "year_bucket",
+				TimeColumn:   "timestamp",
+				Unit:         BucketDay,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(<-table.AsyncInsert(&event{Timestamp: ts})).To(HaveOccurred())
+		})
+
+		It("should select all buckets in time-range", func() {
+			table, err := newTable(&Bucketing{
+				BucketColumn: "year_bucket",
+				TimeColumn:   "timestamp",
+				Unit:         BucketYear,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			queries := [][]interface{}{}
+			session.MockQuery = func(stmt string, values ...interface{}) {
+				queries = append(queries, values)
+			}
+			table.initIterx = func(q driver.QueryI) driver.IterxI {
+				return &mocks.Iterx{
+					CqlQuery: q,
+				}
+			}
+
+			from := time.Date(2016, 6, 1, 0, 0, 0, 0, time.UTC)
+			to := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+			_, err = table.SelectTimeRange(
+				context.Background(),
+				from,
+				to,
+				Partition{"tenant": "t1"},
+				SelectParams{
+					ResultsBind: &[]event{},
+					Concurrency: 1,
+				},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(queries).To(Equal([][]interface{}{
+				[]interface{}{"t1", int64(2016), from, to},
+				[]interface{}{"t1", int64(2017), from, to},
+			}))
+
+			_, err = table.SelectTimeRange(
+				context.Background(), to, from, nil, SelectParams{ResultsBind: &[]event{}},
+			)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,1020 @@
+package cassandra
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+)
+
+// ParsedSchema contains the keyspaces, tables and user-defined types
+// parsed from CQL DDL statements. See #ParseCQL.
+type ParsedSchema struct {
+	Keyspaces []KeyspaceConfig
+	Tables    []ParsedTable
+	Types     []ParsedType
+	// If greater than zero, #Apply waits (until this timeout) for all nodes
+	// to agree on schema-version after creating each keyspace, type and table.
+	// Not set by parsing. See #WaitForSchemaAgreement.
+	SchemaAgreementTimeout time.Duration
+
+	// Creates the iterators for reading system-tables in #Apply,
+	// driver.NewIterx is used if this is nil.
+	// This facilitates mocking by allowing overwriting this
+	initIterx func(q driver.QueryI) driver.IterxI
+}
+
+// ParsedTable is a table parsed from CREATE TABLE statement.
+// The Definition can be directly used with #NewTable, and has
+// column-names as keys.
+type ParsedTable struct {
+	// Blank if the table-name isn't keyspace-qualified,
+	// and no keyspace was selected using USE statement.
+	Keyspace   string
+	Name       string
+	Definition map[string]TableColumn
+	Options    *TableOptions
+}
+
+// ParsedType is a user-defined type parsed from CREATE TYPE statement.
+// The Fields only have Name and DataType set.
+type ParsedType struct {
+	Keyspace string
+	Name     string
+	Fields   []TableColumn
+}
+
+// ParseError is returned when the CQL contains invalid or unsupported syntax.
+type ParseError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("Line %d, Column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// ParseCQLFile reads the provided file and parses its statements. See #ParseCQL.
+func ParseCQLFile(path string) (*ParsedSchema, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCQL(string(data))
+}
+
+// ParseCQL parses the CREATE KEYSPACE, CREATE TABLE, CREATE TYPE and USE
+// statements from provided CQL. A *ParseError, with the line and column of
+// errored token, is returned for any other statements or unsupported syntax
+// (such as COMPACT STORAGE).
+func ParseCQL(cql string) (*ParsedSchema, error) {
+	tokens, err := tokenizeCQL(cql)
+	if err != nil {
+		return nil, err
+	}
+	p := &cqlParser{
+		tokens: tokens,
+	}
+	return p.parse()
+}
+
+// Apply creates the parsed keyspaces, user-defined types and tables in database
+// (if they don't exist). Tables and types can also be created in existing
+// keyspaces not created by the parsed CQL. All names are validated before
+// creating anything. Returns the created tables, with "keyspace.table"
+// names as keys.
+func (ps *ParsedSchema) Apply(session driver.SessionI) (map[string]*Table, error) {
+	err := ps.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	keyspaces := make(map[string]*Keyspace)
+	for _, kc := range ps.Keyspaces {
+		if kc.SchemaAgreementTimeout == 0 {
+			kc.SchemaAgreementTimeout = ps.SchemaAgreementTimeout
+		}
+		keyspace := &Keyspace{
+			durableWrites: true,
+			initIterx:     ps.initIterx,
+		}
+		_, err = keyspace.create(session, kc)
+		if err != nil {
+			return nil, err
+		}
+		keyspaces[kc.Name] = keyspace
+	}
+	// Tables and types can reference existing keyspaces
+	keyspace := func(name string) *Keyspace {
+		if keyspaces[name] == nil {
+			keyspaces[name] = &Keyspace{
+				name:          name,
+				durableWrites: true,
+				initIterx:     ps.initIterx,
+			}
+		}
+		return keyspaces[name]
+	}
+
+	for _, udt := range ps.Types {
+		fields := make([]string, len(udt.Fields))
+		for i, field := range udt.Fields {
+			fields[i] = fmt.Sprintf("%s %s", QuoteIdentifier(field.Name), field.DataType)
+		}
+		err = session.Query(fmt.Sprintf(
+			"CREATE TYPE IF NOT EXISTS %s.%s (%s)",
+			QuoteIdentifier(udt.Keyspace),
+			QuoteIdentifier(udt.Name),
+			strings.Join(fields, ", "),
+		)).Exec()
+		if err != nil {
+			return nil, err
+		}
+		err = awaitSchemaAgreement(session, ps.SchemaAgreementTimeout, ps.initIterx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tables := make(map[string]*Table)
+	for _, pt := range ps.Tables {
+		definition := pt.Definition
+		tc := &TableConfig{
+			Keyspace:               keyspace(pt.Keyspace),
+			Name:                   pt.Name,
+			Options:                pt.Options,
+			SchemaAgreementTimeout: ps.SchemaAgreementTimeout,
+		}
+		table, err := NewTable(session, tc, &definition)
+		if err != nil {
+			return nil, err
+		}
+		tables[fmt.Sprintf("%s.%s", pt.Keyspace, pt.Name)] = table
+	}
+	return tables, nil
+}
+
+// validate checks the keyspace, type, table and column names.
+func (ps *ParsedSchema) validate() error {
+	for _, kc := range ps.Keyspaces {
+		err := ValidateName(kc.Name)
+		if err != nil {
+			return err
+		}
+	}
+	for _, udt := range ps.Types {
+		for _, name := range []string{udt.Keyspace, udt.Name} {
+			err := ValidateName(name)
+			if err != nil {
+				return fmt.Errorf("Type \"%s.%s\": %s", udt.Keyspace, udt.Name, err)
+			}
+		}
+		for _, field := range udt.Fields {
+			err := ValidateIdentifier(field.Name)
+			if err != nil {
+				return fmt.Errorf("Type \"%s.%s\": %s", udt.Keyspace, udt.Name, err)
+			}
+		}
+	}
+	for _, pt := range ps.Tables {
+		for _, name := range []string{pt.Keyspace, pt.Name} {
+			err := ValidateName(name)
+			if err != nil {
+				return fmt.Errorf("Table \"%s.%s\": %s", pt.Keyspace, pt.Name, err)
+			}
+		}
+		for _, column := range pt.Definition {
+			err := ValidateIdentifier(column.Name)
+			if err != nil {
+				return fmt.Errorf("Table \"%s.%s\": %s", pt.Keyspace, pt.Name, err)
+			}
+		}
+		err := pt.Options.validate()
+		if err != nil {
+			return fmt.Errorf("Table \"%s.%s\": %s", pt.Keyspace, pt.Name, err)
+		}
+	}
+	return nil
+}
+
+// cqlTokenType is the type of lexical token in CQL.
+type cqlTokenType int
+
+const (
+	tokenEOF cqlTokenType = iota
+	tokenIdentifier
+	tokenQuotedIdentifier
+	tokenString
+	tokenNumber
+	tokenSymbol
+)
+
+type cqlToken struct {
+	typ    cqlTokenType
+	value  string
+	line   int
+	column int
+}
+
+// tokenizeCQL splits the CQL into tokens, skipping whitespaces and comments.
+// Unquoted identifiers retain their case, since keywords are matched
+// case-insensitively, while the identifiers are lowercased by parser.
+func tokenizeCQL(cql string) ([]cqlToken, error) {
+	runes := []rune(cql)
+	tokens := []cqlToken{}
+	line, column := 1, 1
+
+	advance := func(n int) {
+		for i := 0; i < n; i++ {
+			if runes[0] == '\n' {
+				line++
+				column = 1
+			} else {
+				column++
+			}
+			runes = runes[1:]
+		}
+	}
+	errorAt := func(l int, c int, msg string) error {
+		return &ParseError{Line: l, Column: c, Message: msg}
+	}
+
+	for len(runes) > 0 {
+		r := runes[0]
+		startLine, startColumn := line, column
+
+		switch {
+		case unicode.IsSpace(r):
+			advance(1)
+
+		// Single-line comments
+		case r == '-' && len(runes) > 1 && runes[1] == '-',
+			r == '/' && len(runes) > 1 && runes[1] == '/':
+			for len(runes) > 0 && runes[0] != '\n' {
+				advance(1)
+			}
+
+		// Multi-line comments
+		case r == '/' && len(runes) > 1 && runes[1] == '*':
+			advance(2)
+			for len(runes) > 1 && !(runes[0] == '*' && runes[1] == '/') {
+				advance(1)
+			}
+			if len(runes) < 2 {
+				return nil, errorAt(startLine, startColumn, "Unterminated comment")
+			}
+			advance(2)
+
+		// String-literals and quoted-identifiers, with the quotes
+		// escaped by doubling them
+		case r == '\'' || r == '"':
+			value := ""
+			advance(1)
+			for {
+				if len(runes) == 0 {
+					return nil, errorAt(startLine, startColumn, "Unterminated quotes")
+				}
+				if runes[0] == r {
+					if len(runes) > 1 && runes[1] == r {
+						value += string(r)
+						advance(2)
+						continue
+					}
+					advance(1)
+					break
+				}
+				value += string(runes[0])
+				advance(1)
+			}
+			typ := tokenString
+			if r == '"' {
+				typ = tokenQuotedIdentifier
+			}
+			tokens = append(tokens, cqlToken{typ, value, startLine, startColumn})
+
+		case unicode.IsLetter(r) || r == '_':
+			value := ""
+			for len(runes) > 0 && (unicode.IsLetter(runes[0]) ||
+				unicode.IsDigit(runes[0]) || runes[0] == '_') {
+				value += string(runes[0])
+				advance(1)
+			}
+			tokens = append(tokens, cqlToken{
+				tokenIdentifier, value, startLine, startColumn,
+			})
+
+		case unicode.IsDigit(r) ||
+			(r == '-' && len(runes) > 1 && unicode.IsDigit(runes[1])):
+			value := string(r)
+			advance(1)
+			for len(runes) > 0 && (unicode.IsDigit(runes[0]) || runes[0] == '.' ||
+				runes[0] == 'e' || runes[0] == 'E') {
+				value += string(runes[0])
+				advance(1)
+			}
+			tokens = append(tokens, cqlToken{tokenNumber, value, startLine, startColumn})
+
+		case strings.ContainsRune("(),;.=<>{}:", r):
+			tokens = append(tokens, cqlToken{
+				tokenSymbol, string(r), startLine, startColumn,
+			})
+			advance(1)
+
+		default:
+			return nil, errorAt(
+				startLine, startColumn, fmt.Sprintf("Unexpected character '%c'", r),
+			)
+		}
+	}
+
+	tokens = append(tokens, cqlToken{tokenEOF, "", line, column})
+	return tokens, nil
+}
+
+// cqlParser parses the statements from tokenized CQL.
+type cqlParser struct {
+	tokens []cqlToken
+	pos    int
+	// Keyspace selected using USE statement
+	keyspace string
+	schema   ParsedSchema
+}
+
+func (p *cqlParser) peek() cqlToken {
+	return p.tokens[p.pos]
+}
+
+func (p *cqlParser) next() cqlToken {
+	t := p.tokens[p.pos]
+	if t.typ != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *cqlParser) errorf(t cqlToken, format string, args ...interface{}) error {
+	return &ParseError{
+		Line:    t.line,
+		Column:  t.column,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// describe returns the token as used in error-messages.
+func (t cqlToken) describe() string {
+	switch t.typ {
+	case tokenEOF:
+		return "end of input"
+	case tokenString:
+		return quoteString(t.value)
+	case tokenQuotedIdentifier:
+		return `"` + t.value + `"`
+	}
+	return t.value
+}
+
+// isKeyword checks if the next token is the specified keyword.
+func (p *cqlParser) isKeyword(keyword string) bool {
+	t := p.peek()
+	return t.typ == tokenIdentifier && strings.EqualFold(t.value, keyword)
+}
+
+// acceptKeywords consumes the specified keyword-sequence if the next
+// tokens match it, and reports if the keywords were consumed.
+func (p *cqlParser) acceptKeywords(keywords ...string) bool {
+	for i, keyword := range keywords {
+		t := p.tokens[p.pos+i]
+		if t.typ != tokenIdentifier || !strings.EqualFold(t.value, keyword) {
+			return false
+		}
+	}
+	p.pos += len(keywords)
+	return true
+}
+
+func (p *cqlParser) expectKeyword(keyword string) error {
+	if !p.isKeyword(keyword) {
+		return p.errorf(p.peek(), "Expected %s, found %s", keyword, p.peek().describe())
+	}
+	p.next()
+	return nil
+}
+
+func (p *cqlParser) isSymbol(symbol string) bool {
+	t := p.peek()
+	return t.typ == tokenSymbol && t.value == symbol
+}
+
+func (p *cqlParser) expectSymbol(symbol string) error {
+	if !p.isSymbol(symbol) {
+		return p.errorf(p.peek(), "Expected '%s', found %s", symbol, p.peek().describe())
+	}
+	p.next()
+	return nil
+}
+
+// identifier parses an identifier. Unquoted identifiers are lowercased.
+func (p *cqlParser) identifier() (string, error) {
+	t := p.next()
+	switch t.typ {
+	case tokenIdentifier:
+		return strings.ToLower(t.value), nil
+	case tokenQuotedIdentifier:
+		return t.value, nil
+	}
+	return "", p.errorf(t, "Expected identifier, found %s", t.describe())
+}
+
+// qualifiedName parses [keyspace.]name, defaulting to keyspace from USE statement.
+func (p *cqlParser) qualifiedName() (string, string, error) {
+	name, err := p.identifier()
+	if err != nil {
+		return "", "", err
+	}
+	if !p.isSymbol(".") {
+		return p.keyspace, name, nil
+	}
+	p.next()
+	table, err := p.identifier()
+	return name, table, err
+}
+
+func (p *cqlParser) parse() (*ParsedSchema, error) {
+	for p.peek().typ != tokenEOF {
+		if p.isSymbol(";") {
+			p.next()
+			continue
+		}
+
+		t := p.peek()
+		var err error
+		switch {
+		case p.acceptKeywords("USE"):
+			p.keyspace, err = p.identifier()
+		case p.acceptKeywords("CREATE", "KEYSPACE"), p.acceptKeywords("CREATE", "SCHEMA"):
+			err = p.createKeyspace()
+		case p.acceptKeywords("CREATE", "TABLE"),
+			p.acceptKeywords("CREATE", "COLUMNFAMILY"):
+			err = p.createTable()
+		case p.acceptKeywords("CREATE", "TYPE"):
+			err = p.createType()
+		default:
+			err = p.errorf(t, "Unsupported statement starting with %s", t.describe())
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek().typ != tokenEOF {
+			err = p.expectSymbol(";")
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &p.schema, nil
+}
+
+// createKeyspace parses:
+//  [IF NOT EXISTS] name WITH replication = {...} [AND durable_writes = bool]
+func (p *cqlParser) createKeyspace() error {
+	p.acceptKeywords("IF", "NOT", "EXISTS")
+	name, err := p.identifier()
+	if err != nil {
+		return err
+	}
+	kc := KeyspaceConfig{
+		Name: name,
+	}
+
+	err = p.expectKeyword("WITH")
+	if err != nil {
+		return err
+	}
+	for {
+		propToken := p.peek()
+		prop, err := p.identifier()
+		if err != nil {
+			return err
+		}
+		err = p.expectSymbol("=")
+		if err != nil {
+			return err
+		}
+
+		switch prop {
+		case "replication":
+			err = p.replication(&kc)
+		case "durable_writes":
+			var durableWrites bool
+			durableWrites, err = p.boolean()
+			kc.DurableWrites = &durableWrites
+		default:
+			err = p.errorf(propToken, "Unsupported keyspace-property: %s", prop)
+		}
+		if err != nil {
+			return err
+		}
+
+		if !p.acceptKeywords("AND") {
+			break
+		}
+	}
+
+	p.schema.Keyspaces = append(p.schema.Keyspaces, kc)
+	return nil
+}
+
+// replication parses the replication-map into KeyspaceConfig.
+func (p *cqlParser) replication(kc *KeyspaceConfig) error {
+	t := p.peek()
+	options, err := p.mapLiteral()
+	if err != nil {
+		return err
+	}
+
+	kc.ReplicationStrategyArgs = make(map[string]int)
+	for key, value := range options {
+		if key == "class" {
+			kc.ReplicationStrategy = value
+			continue
+		}
+		rf, err := strconv.Atoi(value)
+		if err != nil {
+			return p.errorf(t, "Invalid replication-factor for \"%s\": %s", key, value)
+		}
+		kc.ReplicationStrategyArgs[key] = rf
+	}
+
+	err = validateReplication(kc.ReplicationStrategy, kc.ReplicationStrategyArgs)
+	if err != nil {
+		return p.errorf(t, "%s", err)
+	}
+	return nil
+}
+
+// createTable parses:
+//  [IF NOT EXISTS] [keyspace.]name (column-definitions) [WITH table-properties]
+func (p *cqlParser) createTable() error {
+	p.acceptKeywords("IF", "NOT", "EXISTS")
+	nameToken := p.peek()
+	keyspace, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+
+	table := ParsedTable{
+		Keyspace:   keyspace,
+		Name:       name,
+		Definition: make(map[string]TableColumn),
+	}
+	partitionKeys, clusteringKeys, err := p.tableColumns(&table)
+	if err != nil {
+		return err
+	}
+	if len(partitionKeys) == 0 {
+		return p.errorf(nameToken, "No PRIMARY KEY specified for table %s", name)
+	}
+
+	for i, column := range partitionKeys {
+		def := table.Definition[column]
+		def.PrimaryKeyIndex = "0"
+		if len(partitionKeys) > 1 {
+			def.PartitionKeyIndex = strconv.Itoa(i)
+		}
+		table.Definition[column] = def
+	}
+	for i, column := range clusteringKeys {
+		def := table.Definition[column]
+		def.PrimaryKeyIndex = strconv.Itoa(i + 1)
+		table.Definition[column] = def
+	}
+
+	if p.acceptKeywords("WITH") {
+		err = p.tableProperties(&table)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = schemaFromDefinition(&table.Definition)
+	if err != nil {
+		return p.errorf(nameToken, "%s", err)
+	}
+	p.schema.Tables = append(p.schema.Tables, table)
+	return nil
+}
+
+// tableColumns parses the column-definitions and primary-key of table.
+// Returns the partition-key and clustering-key columns.
+func (p *cqlParser) tableColumns(table *ParsedTable) ([]string, []string, error) {
+	var partitionKeys, clusteringKeys []string
+
+	err := p.expectSymbol("(")
+	if err != nil {
+		return nil, nil, err
+	}
+	for {
+		t := p.peek()
+		if p.acceptKeywords("PRIMARY", "KEY") {
+			if partitionKeys != nil {
+				return nil, nil, p.errorf(t, "Multiple PRIMARY KEY definitions")
+			}
+			partitionKeys, clusteringKeys, err = p.primaryKey()
+			if err != nil {
+				return nil, nil, err
+			}
+		} else {
+			column, err := p.identifier()
+			if err != nil {
+				return nil, nil, err
+			}
+			if _, exists := table.Definition[column]; exists {
+				return nil, nil, p.errorf(t, "Duplicate column: %s", column)
+			}
+			dataType, err := p.dataType()
+			if err != nil {
+				return nil, nil, err
+			}
+
+			def := TableColumn{
+				Name:     column,
+				DataType: dataType,
+			}
+			if p.acceptKeywords("STATIC") {
+				def.Static = true
+			}
+			if p.acceptKeywords("PRIMARY", "KEY") {
+				if partitionKeys != nil {
+					return nil, nil, p.errorf(t, "Multiple PRIMARY KEY definitions")
+				}
+				partitionKeys = []string{column}
+			}
+			table.Definition[column] = def
+		}
+
+		if p.isSymbol(")") {
+			p.next()
+			break
+		}
+		err = p.expectSymbol(",")
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for _, column := range append(partitionKeys, clusteringKeys...) {
+		if _, exists := table.Definition[column]; !exists {
+			return nil, nil, p.errorf(
+				p.peek(), "PRIMARY KEY column %s is not defined in table", column,
+			)
+		}
+	}
+	return partitionKeys, clusteringKeys, nil
+}
+
+// primaryKey parses:
+//  (partition-key [, clustering-columns])
+// where partition-key is either a column or (column, column...).
+func (p *cqlParser) primaryKey() ([]string, []string, error) {
+	err := p.expectSymbol("(")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var partitionKeys []string
+	if p.isSymbol("(") {
+		partitionKeys, err = p.identifierList()
+	} else {
+		var column string
+		column, err = p.identifier()
+		partitionKeys = []string{column}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clusteringKeys := []string{}
+	for p.isSymbol(",") {
+		p.next()
+		column, err := p.identifier()
+		if err != nil {
+			return nil, nil, err
+		}
+		clusteringKeys = append(clusteringKeys, column)
+	}
+	return partitionKeys, clusteringKeys, p.expectSymbol(")")
+}
+
+// identifierList parses: (identifier, identifier...)
+func (p *cqlParser) identifierList() ([]string, error) {
+	err := p.expectSymbol("(")
+	if err != nil {
+		return nil, err
+	}
+	identifiers := []string{}
+	for {
+		identifier, err := p.identifier()
+		if err != nil {
+			return nil, err
+		}
+		identifiers = append(identifiers, identifier)
+		if !p.isSymbol(",") {
+			break
+		}
+		p.next()
+	}
+	return identifiers, p.expectSymbol(")")
+}
+
+// dataType parses data-types, including collections, tuples, frozen and
+// user-defined types. Returns the data-type as used in CQL, such as:
+// "map<text, frozen<list<int>>>".
+func (p *cqlParser) dataType() (string, error) {
+	t := p.next()
+	var dataType string
+	switch t.typ {
+	case tokenIdentifier:
+		dataType = strings.ToLower(t.value)
+	case tokenQuotedIdentifier:
+		dataType = QuoteIdentifier(t.value)
+	case tokenString:
+		// Custom types are specified as string-literals
+		return quoteString(t.value), nil
+	default:
+		return "", p.errorf(t, "Expected data-type, found %s", t.describe())
+	}
+
+	// User-defined types can be keyspace-qualified
+	if p.isSymbol(".") {
+		p.next()
+		udt, err := p.identifier()
+		if err != nil {
+			return "", err
+		}
+		return dataType + "." + QuoteIdentifier(udt), nil
+	}
+	if !p.isSymbol("<") {
+		return dataType, nil
+	}
+
+	p.next()
+	params := []string{}
+	for {
+		param, err := p.dataType()
+		if err != nil {
+			return "", err
+		}
+		params = append(params, param)
+		if !p.isSymbol(",") {
+			break
+		}
+		p.next()
+	}
+	err := p.expectSymbol(">")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s<%s>", dataType, strings.Join(params, ", ")), nil
+}
+
+// tableProperties parses the table-properties following WITH keyword.
+func (p *cqlParser) tableProperties(table *ParsedTable) error {
+	options := &TableOptions{}
+	hasOptions := false
+
+	for {
+		t := p.peek()
+		if p.acceptKeywords("CLUSTERING", "ORDER", "BY") {
+			err := p.clusteringOrder(table)
+			if err != nil {
+				return err
+			}
+		} else if p.acceptKeywords("COMPACT", "STORAGE") {
+			return p.errorf(t, "COMPACT STORAGE is not supported")
+		} else {
+			err := p.tableProperty(options)
+			if err != nil {
+				return err
+			}
+			hasOptions = true
+		}
+
+		if !p.acceptKeywords("AND") {
+			break
+		}
+	}
+
+	if hasOptions {
+		table.Options = options
+	}
+	return nil
+}
+
+// clusteringOrder parses: (column ASC|DESC, ...)
+func (p *cqlParser) clusteringOrder(table *ParsedTable) error {
+	err := p.expectSymbol("(")
+	if err != nil {
+		return err
+	}
+	for {
+		t := p.peek()
+		column, err := p.identifier()
+		if err != nil {
+			return err
+		}
+		def, exists := table.Definition[column]
+		if !exists || def.PrimaryKeyIndex == "" || def.PrimaryKeyIndex == "0" {
+			return p.errorf(t, "%s is not a clustering-column", column)
+		}
+
+		orderToken := p.next()
+		order := strings.ToUpper(orderToken.value)
+		if orderToken.typ != tokenIdentifier || (order != "ASC" && order != "DESC") {
+			return p.errorf(orderToken, "Expected ASC or DESC, found %s", orderToken.describe())
+		}
+		def.PrimaryKeyOrder = order
+		table.Definition[column] = def
+
+		if !p.isSymbol(",") {
+			break
+		}
+		p.next()
+	}
+	return p.expectSymbol(")")
+}
+
+// tableProperty parses a single "property = value" into TableOptions.
+// Unknown properties are added to TableOptions.Extra as-is.
+func (p *cqlParser) tableProperty(options *TableOptions) error {
+	propToken := p.peek()
+	prop, err := p.identifier()
+	if err != nil {
+		return err
+	}
+	err = p.expectSymbol("=")
+	if err != nil {
+		return err
+	}
+
+	valueToken := p.peek()
+	switch prop {
+	case "caching":
+		options.Caching, err = p.mapLiteral()
+	case "compaction":
+		options.Compaction, err = p.mapLiteral()
+	case "compression":
+		options.Compression, err = p.mapLiteral()
+	case "comment":
+		options.Comment, err = p.stringLiteral()
+	case "speculative_retry":
+		options.SpeculativeRetry, err = p.stringLiteral()
+	case "bloom_filter_fp_chance":
+		var value float64
+		value, err = strconv.ParseFloat(p.next().value, 64)
+		options.BloomFilterFPChance = &value
+	case "default_time_to_live":
+		var value int
+		value, err = strconv.Atoi(p.next().value)
+		options.DefaultTimeToLive = &value
+	case "gc_grace_seconds":
+		var value int
+		value, err = strconv.Atoi(p.next().value)
+		options.GCGraceSeconds = &value
+	default:
+		var value string
+		value, err = p.literal()
+		if options.Extra == nil {
+			options.Extra = make(map[string]string)
+		}
+		options.Extra[prop] = value
+	}
+
+	if err == nil {
+		return nil
+	}
+	if _, isParseErr := err.(*ParseError); isParseErr {
+		return err
+	}
+	return p.errorf(
+		valueToken, "Invalid value for table-property %s: %s", propToken.value, err,
+	)
+}
+
+// literal parses any constant or map-literal, and returns it as used in CQL.
+func (p *cqlParser) literal() (string, error) {
+	if p.isSymbol("{") {
+		m, err := p.mapLiteral()
+		if err != nil {
+			return "", err
+		}
+		return mapLiteral(m), nil
+	}
+
+	t := p.next()
+	switch t.typ {
+	case tokenString:
+		return quoteString(t.value), nil
+	case tokenNumber, tokenIdentifier:
+		return t.value, nil
+	}
+	return "", p.errorf(t, "Expected literal, found %s", t.describe())
+}
+
+func (p *cqlParser) stringLiteral() (string, error) {
+	t := p.next()
+	if t.typ != tokenString {
+		return "", p.errorf(t, "Expected string, found %s", t.describe())
+	}
+	return t.value, nil
+}
+
+func (p *cqlParser) boolean() (bool, error) {
+	t := p.next()
+	value, err := strconv.ParseBool(strings.ToLower(t.value))
+	if t.typ != tokenIdentifier || err != nil {
+		return false, p.errorf(t, "Expected true or false, found %s", t.describe())
+	}
+	return value, nil
+}
+
+// mapLiteral parses: {'key': value, ...}
+// The values are returned as strings, regardless of their CQL-type.
+func (p *cqlParser) mapLiteral() (map[string]string, error) {
+	err := p.expectSymbol("{")
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]string)
+	for !p.isSymbol("}") {
+		key, err := p.stringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		err = p.expectSymbol(":")
+		if err != nil {
+			return nil, err
+		}
+		t := p.next()
+		if t.typ != tokenString && t.typ != tokenNumber && t.typ != tokenIdentifier {
+			return nil, p.errorf(t, "Expected map-value, found %s", t.describe())
+		}
+		m[key] = t.value
+
+		if !p.isSymbol(",") {
+			break
+		}
+		p.next()
+	}
+	return m, p.expectSymbol("}")
+}
+
+// createType parses: [IF NOT EXISTS] [keyspace.]name (field type, ...)
+func (p *cqlParser) createType() error {
+	p.acceptKeywords("IF", "NOT", "EXISTS")
+	keyspace, name, err := p.qualifiedName()
+	if err != nil {
+		return err
+	}
+	udt := ParsedType{
+		Keyspace: keyspace,
+		Name:     name,
+	}
+
+	err = p.expectSymbol("(")
+	if err != nil {
+		return err
+	}
+	for {
+		field, err := p.identifier()
+		if err != nil {
+			return err
+		}
+		dataType, err := p.dataType()
+		if err != nil {
+			return err
+		}
+		udt.Fields = append(udt.Fields, TableColumn{
+			Name:     field,
+			DataType: dataType,
+		})
+
+		if !p.isSymbol(",") {
+			break
+		}
+		p.next()
+	}
+	err = p.expectSymbol(")")
+	if err != nil {
+		return err
+	}
+
+	p.schema.Types = append(p.schema.Types, udt)
+	return nil
+}
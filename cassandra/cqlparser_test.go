@@ -0,0 +1,228 @@
+package cassandra
+
+import (
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	"github.com/TerrexTech/go-commonutils/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CQLParser", func() {
+	const cql = `
+-- Keyspace for events
+CREATE KEYSPACE IF NOT EXISTS events WITH replication = {
+  'class': 'NetworkTopologyStrategy',
+  'datacenter1': 3
+} AND durable_writes = false;
+
+USE events;
+
+/* Address of users */
+CREATE TYPE address (
+  street text,
+  zip int
+);
+
+CREATE TABLE IF NOT EXISTS event_log (
+  tenant text,
+  year_bucket smallint,
+  "eventTime" timestamp,
+  event_id timeuuid,
+  tenant_name text STATIC,
+  tags map<text, frozen<list<text>>>,
+  home frozen<address>,
+  PRIMARY KEY ((tenant, year_bucket), "eventTime", event_id)
+) WITH CLUSTERING ORDER BY ("eventTime" DESC, event_id ASC)
+  AND comment = 'Event''s log'
+  AND default_time_to_live = 86400
+  AND compaction = {'class': 'TimeWindowCompactionStrategy'}
+  AND read_repair_chance = 0.1;
+
+CREATE TABLE other.users (id uuid PRIMARY KEY, Name text);
+`
+
+	It("should parse keyspaces", func() {
+		schema, err := ParseCQL(cql)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(schema.Keyspaces).To(HaveLen(1))
+		kc := schema.Keyspaces[0]
+		Expect(kc.Name).To(Equal("events"))
+		Expect(kc.ReplicationStrategy).To(Equal("NetworkTopologyStrategy"))
+		Expect(kc.ReplicationStrategyArgs).To(Equal(map[string]int{"datacenter1": 3}))
+		Expect(*kc.DurableWrites).To(BeFalse())
+	})
+
+	It("should parse user-defined types", func() {
+		schema, err := ParseCQL(cql)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(schema.Types).To(Equal([]ParsedType{
+			ParsedType{
+				Keyspace: "events",
+				Name:     "address",
+				Fields: []TableColumn{
+					TableColumn{Name: "street", DataType: "text"},
+					TableColumn{Name: "zip", DataType: "int"},
+				},
+			},
+		}))
+	})
+
+	It("should parse tables into table-definitions", func() {
+		schema, err := ParseCQL(cql)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(schema.Tables).To(HaveLen(2))
+
+		table := schema.Tables[0]
+		Expect(table.Keyspace).To(Equal("events"))
+		Expect(table.Name).To(Equal("event_log"))
+		Expect(table.Definition).To(Equal(map[string]TableColumn{
+			"tenant": TableColumn{
+				Name:              "tenant",
+				DataType:          "text",
+				PrimaryKeyIndex:   "0",
+				PartitionKeyIndex: "0",
+			},
+			"year_bucket": TableColumn{
+				Name:              "year_bucket",
+				DataType:          "smallint",
+				PrimaryKeyIndex:   "0",
+				PartitionKeyIndex: "1",
+			},
+			"eventTime": TableColumn{
+				Name:            "eventTime",
+				DataType:        "timestamp",
+				PrimaryKeyIndex: "1",
+				PrimaryKeyOrder: "DESC",
+			},
+			"event_id": TableColumn{
+				Name:            "event_id",
+				DataType:        "timeuuid",
+				PrimaryKeyIndex: "2",
+				PrimaryKeyOrder: "ASC",
+			},
+			"tenant_name": TableColumn{
+				Name:     "tenant_name",
+				DataType: "text",
+				Static:   true,
+			},
+			"tags": TableColumn{
+				Name:     "tags",
+				DataType: "map<text, frozen<list<text>>>",
+			},
+			"home": TableColumn{
+				Name:     "home",
+				DataType: "frozen<address>",
+			},
+		}))
+
+		ttl := 86400
+		Expect(*table.Options).To(Equal(TableOptions{
+			Comment:           "Event's log",
+			DefaultTimeToLive: &ttl,
+			Compaction: map[string]string{
+				"class": "TimeWindowCompactionStrategy",
+			},
+			Extra: map[string]string{
+				"read_repair_chance": "0.1",
+			},
+		}))
+	})
+
+	It("should parse keyspace-qualified tables and inline primary-keys", func() {
+		schema, err := ParseCQL(cql)
+		Expect(err).ToNot(HaveOccurred())
+
+		table := schema.Tables[1]
+		Expect(table.Keyspace).To(Equal("other"))
+		Expect(table.Name).To(Equal("users"))
+		Expect(table.Options).To(BeNil())
+		Expect(table.Definition["id"].PrimaryKeyIndex).To(Equal("0"))
+		// Unquoted identifiers are case-insensitive
+		Expect(table.Definition).To(HaveKey("name"))
+	})
+
+	It("should return error-position for unsupported syntax", func() {
+		_, err := ParseCQL("CREATE TABLE t (\n  id uuid PRIMARY KEY\n) WITH COMPACT STORAGE;")
+		Expect(err).To(HaveOccurred())
+		parseErr, ok := err.(*ParseError)
+		Expect(ok).To(BeTrue())
+		Expect(parseErr.Line).To(Equal(3))
+		Expect(parseErr.Column).To(Equal(8))
+
+		_, err = ParseCQL("USE test;\nDROP TABLE test.t;")
+		Expect(err).To(HaveOccurred())
+		parseErr, ok = err.(*ParseError)
+		Expect(ok).To(BeTrue())
+		Expect(parseErr.Line).To(Equal(2))
+		Expect(parseErr.Column).To(Equal(1))
+	})
+
+	It("should return error on invalid table-definitions", func() {
+		invalidCQL := []string{
+			"CREATE TABLE t (id uuid, data text)",
+			"CREATE TABLE t (id uuid PRIMARY KEY, PRIMARY KEY (id))",
+			"CREATE TABLE t (id uuid, PRIMARY KEY (uid))",
+			"CREATE TABLE t (id uuid, id text, PRIMARY KEY (id))",
+			"CREATE TABLE t (id uuid STATIC, PRIMARY KEY (id))",
+			"CREATE TABLE t (id uuid PRIMARY KEY) WITH CLUSTERING ORDER BY (id ASC)",
+			"CREATE TABLE t (id uuid PRIMARY KEY) WITH gc_grace_seconds = 'a'",
+			"CREATE TABLE t (id map<text, int PRIMARY KEY)",
+			"CREATE TABLE t (id uuid PRIMARY KEY) WITH comment = 'unterminated",
+		}
+		for _, stmt := range invalidCQL {
+			_, err := ParseCQL(stmt)
+			Expect(err).To(HaveOccurred(), stmt)
+		}
+	})
+
+	It("should create the parsed schema", func() {
+		queries := []string{}
+		session := &mocks.Session{
+			MockQuery: func(stmt string, values ...interface{}) {
+				queries = append(queries, utils.StandardizeSpaces(stmt))
+			},
+		}
+		schema, err := ParseCQL(cql)
+		Expect(err).ToNot(HaveOccurred())
+
+		tables, err := schema.Apply(session)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(queries).To(HaveLen(4))
+		Expect(queries[0]).To(HavePrefix("CREATE KEYSPACE IF NOT EXISTS events"))
+		Expect(queries[1]).To(Equal(
+			"CREATE TYPE IF NOT EXISTS events.address (street text, zip int)",
+		))
+		Expect(queries[2]).To(HavePrefix(
+			`CREATE TABLE IF NOT EXISTS events.event_log ("eventTime" timestamp,`,
+		))
+		Expect(queries[2]).To(ContainSubstring("tenant_name text static"))
+		Expect(queries[2]).To(ContainSubstring(
+			`PRIMARY KEY ((tenant, year_bucket), "eventTime", event_id)`,
+		))
+		Expect(queries[3]).To(HavePrefix("CREATE TABLE IF NOT EXISTS other.users"))
+
+		Expect(tables).To(HaveKey("events.event_log"))
+		Expect(tables).To(HaveKey("other.users"))
+	})
+
+	It("should validate names before creating anything", func() {
+		queries := []string{}
+		session := &mocks.Session{
+			MockQuery: func(stmt string, values ...interface{}) {
+				queries = append(queries, stmt)
+			},
+		}
+		schema, err := ParseCQL(`
+CREATE KEYSPACE test WITH replication = {
+  'class': 'SimpleStrategy', 'replication_factor': 1
+};
+CREATE TABLE test."user-data" (id uuid PRIMARY KEY);
+`)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = schema.Apply(session)
+		Expect(err).To(HaveOccurred())
+		Expect(queries).To(BeEmpty())
+	})
+})
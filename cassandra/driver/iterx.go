@@ -8,7 +8,12 @@ import (
 // This can also be used for paging the results.
 type IterxI interface {
 	Close() error
+	Get(dest interface{}) error
+	MapScan(m map[string]interface{}) bool
+	PageState() []byte
+	Scan(dest ...interface{}) bool
 	Select(dest interface{}) error
+	StructScan(dest interface{}) bool
 }
 
 // Iterx is the implementation for IterxI.
@@ -31,7 +36,39 @@ func (i *Iterx) Close() error {
 	return i.iterx.Close()
 }
 
+// Get scans the first row into dest, and closes the iterator.
+// Returns gocql.ErrNotFound if there are no rows.
+func (i *Iterx) Get(dest interface{}) error {
+	return i.iterx.Get(dest)
+}
+
+// MapScan scans the next row into the provided map, with column-names as keys.
+// Returns false if there are no more rows, or an error occurred (see #Close).
+func (i *Iterx) MapScan(m map[string]interface{}) bool {
+	return i.iterx.MapScan(m)
+}
+
+// PageState returns the paging-state for fetching the next page.
+// This is empty if there are no more pages. See QueryI#SetPageState.
+func (i *Iterx) PageState() []byte {
+	return i.iterx.PageState()
+}
+
+// Scan scans the columns of next row into the provided values, in the order
+// the columns are selected. Returns false if there are no more rows, or an
+// error occurred (see #Close).
+func (i *Iterx) Scan(dest ...interface{}) bool {
+	return i.iterx.Scan(dest...)
+}
+
 // Select returns the statement that was used to generate this query.
 func (i *Iterx) Select(dest interface{}) error {
 	return i.iterx.Select(dest)
 }
+
+// StructScan scans the next row into the provided struct-pointer, binding the
+// columns by "db" struct-tags. Returns false if there are no more rows, or an
+// error occurred (see #Close).
+func (i *Iterx) StructScan(dest interface{}) bool {
+	return i.iterx.StructScan(dest)
+}
@@ -1,21 +1,33 @@
 package driver
 
-import cql "github.com/gocql/gocql"
+import (
+	"context"
+
+	cql "github.com/gocql/gocql"
+)
 
 // QueryI is the query-handler for database-session.
 type QueryI interface {
+	Consistency(c cql.Consistency) QueryI
 	GoCqlQuery() *cql.Query
 	Exec() error
+	MapScanCAS(dest map[string]interface{}) (bool, error)
+	ScanCAS(dest ...interface{}) (bool, error)
+	SerialConsistency(cons cql.SerialConsistency) QueryI
 	GetPageSize() uint
 	SetPageSize(n uint) QueryI
+	GetPageState() []byte
+	SetPageState(state []byte) QueryI
 	Release()
 	Statement() string
+	WithContext(ctx context.Context) QueryI
 }
 
 // Query is the query-handler implementation for database-session.
 type Query struct {
-	pageSize uint
-	query    *cql.Query
+	pageSize  uint
+	pageState []byte
+	query     *cql.Query
 }
 
 // GoCqlQuery returns the embedded GoCql query.
@@ -29,6 +41,32 @@ func (q *Query) Exec() error {
 	return err
 }
 
+// ScanCAS executes a lightweight-transaction (an UPDATE or INSERT with
+// IF clause), and returns if it was applied. If not applied, the current
+// values of the columns in IF clause are scanned into dest.
+func (q *Query) ScanCAS(dest ...interface{}) (bool, error) {
+	return q.query.ScanCAS(dest...)
+}
+
+// MapScanCAS executes a lightweight-transaction, same as #ScanCAS, but
+// scans the current values into dest map, with column-names as keys.
+func (q *Query) MapScanCAS(dest map[string]interface{}) (bool, error) {
+	return q.query.MapScanCAS(dest)
+}
+
+// Consistency sets the consistency-level for query.
+func (q *Query) Consistency(c cql.Consistency) QueryI {
+	q.query.Consistency(c)
+	return q
+}
+
+// SerialConsistency sets the consistency-level for the serial-phase
+// (the Paxos round) of lightweight-transactions.
+func (q *Query) SerialConsistency(cons cql.SerialConsistency) QueryI {
+	q.query.SerialConsistency(cons)
+	return q
+}
+
 // GetPageSize returns the current page-size
 func (q *Query) GetPageSize() uint {
 	if q.pageSize == 0 {
@@ -47,6 +85,21 @@ func (q *Query) SetPageSize(n uint) QueryI {
 	return q
 }
 
+// GetPageState returns the paging-state, as set using #SetPageState.
+func (q *Query) GetPageState() []byte {
+	return q.pageState
+}
+
+// SetPageState sets the paging-state for the query to resume paging
+// from a specific page. This also disables the automatic fetching of
+// next pages, so the iterator only returns a single page.
+// See IterxI#PageState for getting the paging-state.
+func (q *Query) SetPageState(state []byte) QueryI {
+	q.pageState = state
+	q.query.PageState(state)
+	return q
+}
+
 // Release releases the query. Released queries cannot be reused.
 func (q *Query) Release() {
 	q.query.Release()
@@ -56,3 +109,10 @@ func (q *Query) Release() {
 func (q *Query) Statement() string {
 	return q.query.Statement()
 }
+
+// WithContext sets the context for query. The query is canceled
+// when the context is canceled.
+func (q *Query) WithContext(ctx context.Context) QueryI {
+	q.query = q.query.WithContext(ctx)
+	return q
+}
@@ -0,0 +1,146 @@
+package cassandra
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Cassandra limits the keyspace and table names to 48 characters.
+const maxNameLength = 48
+
+var (
+	// Valid characters for keyspace, table and column names.
+	identifierRgx = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+	// Identifiers matching this can be used without quotes.
+	unquotedIdentifierRgx = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// reservedKeywords are the CQL keywords which cannot be used as identifiers
+// without quoting. This also includes some non-reserved keywords (such as "key"),
+// which are reserved in older Cassandra versions.
+var reservedKeywords = map[string]bool{
+	"add":          true,
+	"allow":        true,
+	"alter":        true,
+	"and":          true,
+	"apply":        true,
+	"asc":          true,
+	"authorize":    true,
+	"batch":        true,
+	"begin":        true,
+	"by":           true,
+	"columnfamily": true,
+	"create":       true,
+	"default":      true,
+	"delete":       true,
+	"desc":         true,
+	"describe":     true,
+	"drop":         true,
+	"entries":      true,
+	"execute":      true,
+	"from":         true,
+	"full":         true,
+	"grant":        true,
+	"if":           true,
+	"in":           true,
+	"index":        true,
+	"infinity":     true,
+	"insert":       true,
+	"into":         true,
+	"is":           true,
+	"key":          true,
+	"keyspace":     true,
+	"limit":        true,
+	"materialized": true,
+	"mbean":        true,
+	"mbeans":       true,
+	"modify":       true,
+	"nan":          true,
+	"norecursive":  true,
+	"not":          true,
+	"null":         true,
+	"of":           true,
+	"on":           true,
+	"or":           true,
+	"order":        true,
+	"primary":      true,
+	"rename":       true,
+	"replace":      true,
+	"revoke":       true,
+	"schema":       true,
+	"select":       true,
+	"set":          true,
+	"table":        true,
+	"to":           true,
+	"token":        true,
+	"truncate":     true,
+	"unlogged":     true,
+	"unset":        true,
+	"update":       true,
+	"use":          true,
+	"using":        true,
+	"view":         true,
+	"where":        true,
+	"with":         true,
+}
+
+// ValidateName checks if the provided name is valid for a keyspace or table.
+// Such names can only contain alphanumeric characters and underscores,
+// and cannot exceed 48 characters.
+func ValidateName(name string) error {
+	err := ValidateIdentifier(name)
+	if err != nil {
+		return err
+	}
+	if len(name) > maxNameLength {
+		return fmt.Errorf(
+			"Name \"%s\" exceeds the maximum length of %d characters", name, maxNameLength,
+		)
+	}
+	return nil
+}
+
+// ValidateIdentifier checks if the provided identifier (such as a column-name)
+// is valid. Identifiers can only contain alphanumeric characters and underscores.
+// Reserved keywords and mixed-case identifiers are valid, since these are quoted
+// when used in queries. See #QuoteIdentifier.
+func ValidateIdentifier(identifier string) error {
+	if identifier == "" {
+		return errors.New("Identifier cannot be blank")
+	}
+	if !identifierRgx.MatchString(identifier) {
+		return fmt.Errorf(
+			"Invalid identifier \"%s\". Identifiers can only contain"+
+				" alphanumeric characters and underscores",
+			identifier,
+		)
+	}
+	return nil
+}
+
+// QuoteIdentifier returns the identifier as it should be used in CQL statements.
+// Reserved keywords, mixed-case identifiers, and identifiers not starting with
+// a letter are double-quoted, so that they are used as-is by Cassandra.
+// Other identifiers are returned unchanged.
+func QuoteIdentifier(identifier string) string {
+	if unquotedIdentifierRgx.MatchString(identifier) && !reservedKeywords[identifier] {
+		return identifier
+	}
+	return `"` + strings.Replace(identifier, `"`, `""`, -1) + `"`
+}
+
+// quoteIdentifiers applies #QuoteIdentifier to every provided identifier.
+func quoteIdentifiers(identifiers []string) []string {
+	quoted := make([]string, len(identifiers))
+	for i, identifier := range identifiers {
+		quoted[i] = QuoteIdentifier(identifier)
+	}
+	return quoted
+}
+
+// quoteString returns the provided value as a CQL string-literal.
+func quoteString(value string) string {
+	return "'" + strings.Replace(value, "'", "''", -1) + "'"
+}
@@ -0,0 +1,176 @@
+package cassandra
+
+import (
+	"strings"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	"github.com/TerrexTech/go-commonutils/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Identifier", func() {
+	Context("identifier is quoted", func() {
+		It("should not quote lowercase non-reserved identifiers", func() {
+			Expect(QuoteIdentifier("month_bucket")).To(Equal("month_bucket"))
+			Expect(QuoteIdentifier("timestamp")).To(Equal("timestamp"))
+		})
+
+		It("should quote reserved keywords", func() {
+			Expect(QuoteIdentifier("order")).To(Equal(`"order"`))
+			Expect(QuoteIdentifier("key")).To(Equal(`"key"`))
+		})
+
+		It("should quote mixed-case identifiers", func() {
+			Expect(QuoteIdentifier("userID")).To(Equal(`"userID"`))
+		})
+
+		It("should quote identifiers not starting with a letter", func() {
+			Expect(QuoteIdentifier("1col")).To(Equal(`"1col"`))
+			Expect(QuoteIdentifier("_col")).To(Equal(`"_col"`))
+		})
+
+		It("should escape double-quotes", func() {
+			Expect(QuoteIdentifier(`a"b`)).To(Equal(`"a""b"`))
+		})
+	})
+
+	Context("identifier is validated", func() {
+		It("should return error on blank identifiers", func() {
+			Expect(ValidateIdentifier("")).To(HaveOccurred())
+		})
+
+		It("should return error on identifiers with invalid characters", func() {
+			Expect(ValidateIdentifier("col; DROP TABLE x")).To(HaveOccurred())
+			Expect(ValidateIdentifier(`col"`)).To(HaveOccurred())
+		})
+
+		It("should accept reserved and mixed-case identifiers", func() {
+			Expect(ValidateIdentifier("order")).ToNot(HaveOccurred())
+			Expect(ValidateIdentifier("userID")).ToNot(HaveOccurred())
+		})
+
+		It("should return error on names exceeding 48 characters", func() {
+			Expect(ValidateName(strings.Repeat("a", 49))).To(HaveOccurred())
+			Expect(ValidateName(strings.Repeat("a", 48))).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("identifiers are used in queries", func() {
+		var (
+			keyspace  *Keyspace
+			outputStr string
+			session   *mocks.Session
+		)
+
+		BeforeEach(func() {
+			session = &mocks.Session{
+				MockQuery: func(stmt string, values ...interface{}) {
+					outputStr = utils.StandardizeSpaces(stmt)
+				},
+			}
+			var err error
+			keyspace, err = NewKeyspace(session, KeyspaceConfig{
+				Name:        "Test",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outputStr).To(HavePrefix(`CREATE KEYSPACE IF NOT EXISTS "Test" WITH`))
+		})
+
+		It("should return error on invalid keyspace-name", func() {
+			_, err := NewKeyspace(session, KeyspaceConfig{
+				Name:        "test; DROP KEYSPACE x",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return error on invalid table or column names", func() {
+			definition := &map[string]TableColumn{
+				"key": TableColumn{
+					Name:            "key",
+					DataType:        "text",
+					PrimaryKeyIndex: "0",
+				},
+			}
+			_, err := NewTable(session, &TableConfig{
+				Keyspace: keyspace,
+				Name:     "test-table",
+			}, definition)
+			Expect(err).To(HaveOccurred())
+
+			(*definition)["invalid"] = TableColumn{
+				Name:     "invalid column",
+				DataType: "text",
+			}
+			_, err = NewTable(session, &TableConfig{
+				Keyspace: keyspace,
+				Name:     "test_table",
+			}, definition)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should quote reserved and mixed-case identifiers in DDL and DML", func() {
+			definition := &map[string]TableColumn{
+				"key": TableColumn{
+					Name:            "key",
+					DataType:        "text",
+					PrimaryKeyIndex: "0",
+				},
+				"order": TableColumn{
+					Name:            "order",
+					DataType:        "int",
+					PrimaryKeyIndex: "1",
+					PrimaryKeyOrder: "DESC",
+				},
+			}
+			t, err := NewTable(session, &TableConfig{
+				Keyspace: keyspace,
+				Name:     "Orders",
+			}, definition)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outputStr).To(HavePrefix(`CREATE TABLE IF NOT EXISTS "Test"."Orders" (`))
+			Expect(outputStr).To(ContainSubstring(`"key" text`))
+			Expect(outputStr).To(ContainSubstring(`PRIMARY KEY ("key", "order")`))
+			Expect(outputStr).To(HaveSuffix(`WITH CLUSTERING ORDER BY ("order" DESC)`))
+			Expect(t.FullName()).To(Equal(`"Test"."Orders"`))
+
+			var query driver.QueryI
+			t.initIterx = func(q driver.QueryI) driver.IterxI {
+				query = q
+				return &mocks.Iterx{
+					CqlQuery: q,
+				}
+			}
+			_, err = t.Select(SelectParams{
+				ColumnValues: []ColumnComparator{
+					Comparator("key", "a").Eq(),
+				},
+				SelectColumns: []string{"order"},
+				ResultsBind:   &[]map[string]interface{}{},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(query.Statement()).To(Equal(
+				`SELECT "order" FROM "Test"."Orders" WHERE "key"=? `,
+			))
+
+			var insertColumns []string
+			t.initQueryx = func(q driver.QueryI, names []string) driver.QueryxI {
+				query = q
+				insertColumns = names
+				return &mocks.Queryx{
+					CqlQuery:    q,
+					ColumnNames: names,
+				}
+			}
+			err = <-t.AsyncInsert(map[string]interface{}{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(query.Statement()).To(HavePrefix(`INSERT INTO "Test"."Orders" (`))
+			Expect(query.Statement()).To(ContainSubstring(`"order"`))
+			// Unquoted names are used for binding values
+			Expect(insertColumns).To(ConsistOf("key", "order"))
+		})
+	})
+})
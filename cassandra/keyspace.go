@@ -3,7 +3,7 @@ package cassandra
 import (
 	"fmt"
 	"strconv"
-	"strings"
+	"time"
 
 	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
 )
@@ -13,24 +13,46 @@ type KeyspaceConfig struct {
 	Name                    string
 	ReplicationStrategy     string
 	ReplicationStrategyArgs map[string]int
+	// Typed replication-config, such as SimpleStrategy or NetworkTopologyStrategy.
+	// This takes precedence over ReplicationStrategy and ReplicationStrategyArgs.
+	Replication ReplicationConfig
+	// Sets durable_writes for Keyspace. The database-default (true)
+	// is used if this is nil.
+	DurableWrites *bool
+	// Validates the datacenters and replication-factors against the
+	// cluster-topology before executing the query. See #ValidateReplication.
+	ValidateTopology bool
+	// If greater than zero, the DDL operations wait (until this timeout)
+	// for all nodes to agree on schema-version. See #WaitForSchemaAgreement.
+	SchemaAgreementTimeout time.Duration
 }
 
 // Keyspace acts as utility-entity corresponding to Cassandra Keyspace.
 type Keyspace struct {
 	name                    string
+	durableWrites           bool
 	replicationStrategy     string
 	replicationStrategyArgs map[string]int
+	replicationWarnings     []string
+
+	// Creates the iterators for reading system-tables, such as when waiting
+	// for schema-agreement. This is also used by the Keyspace's tables.
+	// This facilitates mocking by allowing overwriting this
+	initIterx func(q driver.QueryI) driver.IterxI
 }
 
 // NewKeyspace creates a new Keyspace-entity instance, and also creates the
 // Keyspace in database if it doesn't exist.
 func NewKeyspace(session driver.SessionI, kc KeyspaceConfig) (*Keyspace, error) {
 	k := &Keyspace{
-		name:                    kc.Name,
-		replicationStrategy:     kc.ReplicationStrategy,
-		replicationStrategyArgs: kc.ReplicationStrategyArgs,
+		durableWrites: true,
+		initIterx:     driver.NewIterx,
 	}
+	return k.create(session, kc)
+}
 
+// create creates the Keyspace in database if it doesn't exist.
+func (k *Keyspace) create(session driver.SessionI, kc KeyspaceConfig) (*Keyspace, error) {
 	queryInitial := "CREATE KEYSPACE IF NOT EXISTS"
 	err := k.manipulationQuery(session, kc, queryInitial)
 	if err != nil {
@@ -54,41 +76,85 @@ func (k *Keyspace) ReplicationStrategyArgs() map[string]int {
 	return k.replicationStrategyArgs
 }
 
-// Parses replicationStrategyArgs and suffixes the provided query with result.
+// DurableWrites returns the durable_writes setting of Keyspace.
+func (k *Keyspace) DurableWrites() bool {
+	return k.durableWrites
+}
+
+// ReplicationWarnings returns the warnings generated when validating the
+// replication-config against cluster-topology. This is only populated
+// if KeyspaceConfig.ValidateTopology was set.
+func (k *Keyspace) ReplicationWarnings() []string {
+	return k.replicationWarnings
+}
+
+// Validates the KeyspaceConfig, sets Keyspace values from it, and
+// suffixes the provided query with replication and durable_writes options.
 func (k *Keyspace) manipulationQuery(
 	session driver.SessionI,
 	kc KeyspaceConfig,
 	queryInitial string,
 ) error {
+	err := ValidateName(kc.Name)
+	if err != nil {
+		return err
+	}
+	class, args := replicationFromConfig(kc)
+	err = validateReplication(class, args)
+	if err != nil {
+		return err
+	}
+	if kc.ValidateTopology {
+		warnings, err := validateTopology(session, kc, k.initIterx)
+		if err != nil {
+			return err
+		}
+		k.replicationWarnings = warnings
+	}
+
+	k.name = kc.Name
+	k.replicationStrategy = class
+	k.replicationStrategyArgs = args
+
 	replicationStrategyArgs := ""
-	for key, value := range k.replicationStrategyArgs {
-		replicationStrategyArgs += fmt.Sprintf("'%s': %s, ", key, strconv.Itoa(value))
+	for _, key := range sortedKeys(args) {
+		replicationStrategyArgs += fmt.Sprintf(
+			",\n%s: %s", quoteString(key), strconv.Itoa(args[key]),
+		)
+	}
+
+	durableWrites := ""
+	if kc.DurableWrites != nil {
+		k.durableWrites = *kc.DurableWrites
+		durableWrites = fmt.Sprintf(
+			"AND durable_writes = %s", strconv.FormatBool(k.durableWrites),
+		)
 	}
-	replicationStrategyArgs = strings.TrimSuffix(replicationStrategyArgs, ", ")
 
-	err := session.Query(
+	err = session.Query(
 		fmt.Sprintf(`
 			%s %s
     		WITH replication = {
-        	'class' : '%s',
-        	%s
-				}`,
+        	'class' : %s%s
+				}
+			%s`,
 			queryInitial,
-			kc.Name,
-			kc.ReplicationStrategy,
+			QuoteIdentifier(kc.Name),
+			quoteString(class),
 			replicationStrategyArgs,
+			durableWrites,
 		)).
 		Exec()
+	if err != nil {
+		return err
+	}
 
-	return err
+	return awaitSchemaAgreement(session, kc.SchemaAgreementTimeout, k.initIterx)
 }
 
-// Alter allows changing replicationStrategy and replicationFactor of Keyspace.
+// Alter allows changing replicationStrategy, replicationFactor
+// and durable_writes of Keyspace.
 func (k *Keyspace) Alter(session driver.SessionI, kc KeyspaceConfig) (*Keyspace, error) {
-	k.name = kc.Name
-	k.replicationStrategy = kc.ReplicationStrategy
-	k.replicationStrategyArgs = kc.ReplicationStrategyArgs
-
 	queryInitial := "ALTER KEYSPACE"
 	err := k.manipulationQuery(session, kc, queryInitial)
 	if err != nil {
@@ -6,6 +6,8 @@ import (
 	"strconv"
 	"strings"
 
+	cql "github.com/gocql/gocql"
+
 	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
 )
 
@@ -28,17 +30,36 @@ func NewTable(
 	if tc.Name == "" {
 		return nil, errors.New("Table name is required")
 	}
+	err := ValidateName(tc.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, column := range *definition {
+		err = ValidateIdentifier(column.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	schema, err := schemaFromDefinition(definition)
 
 	t := &Table{
-		definition: definition,
-		keyspace:   tc.Keyspace,
-		name:       tc.Name,
-		initQueryx: driver.NewQueryx,
-		initIterx:  driver.NewIterx,
-		session:    session,
-		schema:     schema,
+		bucketing:              tc.Bucketing,
+		cursorKey:              tc.CursorKey,
+		definition:             definition,
+		keyspace:               tc.Keyspace,
+		name:                   tc.Name,
+		initQueryx:             driver.NewQueryx,
+		initIterx:              driver.NewIterx,
+		options:                tc.Options,
+		session:                session,
+		schema:                 schema,
+		schemaAgreementTimeout: tc.SchemaAgreementTimeout,
+		serialConsistency:      tc.SerialConsistency,
+		versionColumn:          tc.VersionColumn,
+	}
+	if t.serialConsistency == 0 {
+		t.serialConsistency = cql.Serial
 	}
 
 	tableColumns := ""
@@ -46,26 +67,56 @@ func NewTable(
 	if err != nil {
 		return nil, err
 	}
-	for key, value := range *schema {
-		if key != "WITH CLUSTERING ORDER BY" {
-			tableColumns += fmt.Sprintf("%s %s, ", key, value)
+	for _, column := range t.Columns() {
+		tableColumns += fmt.Sprintf(
+			"%s %s, ", QuoteIdentifier(column), (*schema)[column],
+		)
+	}
+	tableColumns += fmt.Sprintf("PRIMARY KEY %s", (*schema)["PRIMARY KEY"])
+	// Tables without clustering-columns have no clustering-order
+	if (*schema)["WITH CLUSTERING ORDER BY"] != "()" {
+		clusteringOrder = fmt.Sprintf(
+			"WITH CLUSTERING ORDER BY %s", (*schema)["WITH CLUSTERING ORDER BY"],
+		)
+	}
+
+	err = tc.Options.validate()
+	if err != nil {
+		return nil, err
+	}
+	err = tc.Bucketing.validate(t)
+	if err != nil {
+		return nil, err
+	}
+	err = t.validateVersionColumn()
+	if err != nil {
+		return nil, err
+	}
+	tableOptions := strings.Join(tc.Options.toCql(), " AND ")
+	if tableOptions != "" {
+		if clusteringOrder == "" {
+			tableOptions = "WITH " + tableOptions
 		} else {
-			clusteringOrder += fmt.Sprintf("%s %s", key, value)
+			tableOptions = "AND " + tableOptions
 		}
 	}
-	tableColumns = strings.TrimSuffix(tableColumns, ", ")
 
 	query := fmt.Sprintf(`
-    CREATE TABLE IF NOT EXISTS %s (%s) %s`,
+    CREATE TABLE IF NOT EXISTS %s (%s) %s %s`,
 		t.FullName(),
 		tableColumns,
 		clusteringOrder,
+		tableOptions,
 	)
 
 	err = session.Query(query).Exec()
 	if err != nil {
 		return nil, err
 	}
+	err = awaitSchemaAgreement(session, t.schemaAgreementTimeout, t.keyspace.initIterx)
+	if err != nil {
+		return nil, err
+	}
 	return t, nil
 }
 
@@ -84,10 +135,12 @@ func schemaFromDefinition(
 	//   2:map[column:uuid order:ASC]
 	//  ]
 	primaryKeyDefinition := make(map[int]map[string]string)
+	// Columns of composite partition-key, with PartitionKeyIndex as keys
+	partitionKeys := make(map[int]string)
 
 	for _, columnDefinition := range *tableDefinition {
 		columnName := columnDefinition.Name
-		schema[columnName] = columnDefinition.DataType
+		schema[columnName] = columnDataType(columnDefinition)
 		// Convert string key-index to integer, and build primary-key-schema
 		primaryKeyIndexStr := columnDefinition.PrimaryKeyIndex
 		primaryKeyOrder := columnDefinition.PrimaryKeyOrder
@@ -99,6 +152,37 @@ func schemaFromDefinition(
 					fmt.Sprintf(" Errored Key: \"%s\"", columnName),
 			)
 		}
+		if columnDefinition.Static && primaryKeyIndexStr != "" {
+			return nil, errors.New(
+				"Primary-key columns cannot be static." +
+					fmt.Sprintf(" Errored Key: \"%s\"", columnName),
+			)
+		}
+
+		if columnDefinition.PartitionKeyIndex != "" {
+			if primaryKeyIndexStr != "0" {
+				return nil, errors.New(
+					"PartitionKeyIndex can only be specified if PrimaryKeyIndex is 0." +
+						fmt.Sprintf(" Errored Key: \"%s\"", columnName),
+				)
+			}
+			partitionKeyIndex, err := strconv.Atoi(columnDefinition.PartitionKeyIndex)
+			if err != nil {
+				return nil, err
+			}
+			if partitionKeys[partitionKeyIndex] != "" {
+				return nil, errors.New(
+					"Duplicate Partition Key Index found." +
+						fmt.Sprintf(
+							" Previous key with same index: \"%s\". Current Key: \"%s\"",
+							partitionKeys[partitionKeyIndex],
+							columnName,
+						),
+				)
+			}
+			partitionKeys[partitionKeyIndex] = columnName
+			continue
+		}
 
 		if primaryKeyIndexStr != "" {
 			primaryKeyIndex, _ := strconv.Atoi(primaryKeyIndexStr)
@@ -128,6 +212,39 @@ func schemaFromDefinition(
 			return nil, err
 		}
 	}
+	if len(partitionKeys) > 0 {
+		if primaryKeyDefinition[0] != nil {
+			return nil, errors.New(
+				"PartitionKeyIndex is required for all partition-key columns" +
+					" when using composite partition-key." +
+					fmt.Sprintf(" Errored Key: \"%s\"", primaryKeyDefinition[0]["column"]),
+			)
+		}
+		partitionKeyColumns := make([]string, len(partitionKeys))
+		for index, column := range partitionKeys {
+			if index < 0 || index >= len(partitionKeys) {
+				return nil, errors.New(
+					"PartitionKeyIndexes must be sequential, starting from 0." +
+						fmt.Sprintf(" Errored Key: \"%s\"", column),
+				)
+			}
+			partitionKeyColumns[index] = QuoteIdentifier(column)
+		}
+		// Sample composite partition-key schema:
+		//  map[columns:(year_bucket, user_id)]
+		primaryKeyDefinition[0] = map[string]string{
+			"columns": fmt.Sprintf("(%s)", strings.Join(partitionKeyColumns, ", ")),
+		}
+	}
+
+	for index, primaryKey := range primaryKeyDefinition {
+		if index < 0 || index >= len(primaryKeyDefinition) {
+			return nil, errors.New(
+				"PrimaryKeyIndexes must be sequential, starting from 0." +
+					fmt.Sprintf(" Errored Key: \"%s\"", primaryKey["column"]),
+			)
+		}
+	}
 	primaryKeyStr, clusteringKeyOrderStr := primaryKeySchemaToQueryString(&primaryKeyDefinition)
 	schema["PRIMARY KEY"] = fmt.Sprintf("(%s)", primaryKeyStr)
 	schema["WITH CLUSTERING ORDER BY"] = fmt.Sprintf("(%s)", clusteringKeyOrderStr)
@@ -135,6 +252,15 @@ func schemaFromDefinition(
 	return &schema, nil
 }
 
+// columnDataType returns the column data-type as used in table-schema.
+// Static columns have "static" suffixed to their data-type.
+func columnDataType(column TableColumn) string {
+	if column.Static {
+		return column.DataType + " static"
+	}
+	return column.DataType
+}
+
 // buildPrimaryKeySchema returns map with Primary-Key schema using
 // the provided values. The primaryKeyIndexStr must have a valid value
 // (an integer in string format, example: "1"), else a blank map is returned.
@@ -189,8 +315,13 @@ func primaryKeySchemaToQueryString(
 ) (string, string) {
 	primaryKeys := make([][]string, len(*primaryKeyDefinition))
 	for key, value := range *primaryKeyDefinition {
+		// Composite partition-keys are already formatted
+		column := value["columns"]
+		if column == "" {
+			column = QuoteIdentifier(value["column"])
+		}
 		primaryKeys[key] = []string{
-			value["column"],
+			column,
 			value["order"],
 		}
 	}
@@ -0,0 +1,66 @@
+package cassandra
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PartitionKeyColumns returns the partition-key columns (column-names
+// as in database), in the order they appear in partition-key.
+func (t *Table) PartitionKeyColumns() []string {
+	columns := []TableColumn{}
+	for _, column := range *t.Definition() {
+		if column.PrimaryKeyIndex == "0" {
+			columns = append(columns, column)
+		}
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		// PartitionKeyIndex is only set for composite partition-keys
+		a, _ := strconv.Atoi(columns[i].PartitionKeyIndex)
+		b, _ := strconv.Atoi(columns[j].PartitionKeyIndex)
+		return a < b
+	})
+
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+	return names
+}
+
+// ClusteringColumns returns the clustering-columns, in the order
+// they appear in primary-key.
+func (t *Table) ClusteringColumns() []TableColumn {
+	columns := []TableColumn{}
+	for _, column := range *t.Definition() {
+		if column.PrimaryKeyIndex != "" && column.PrimaryKeyIndex != "0" {
+			columns = append(columns, column)
+		}
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		a, _ := strconv.Atoi(columns[i].PrimaryKeyIndex)
+		b, _ := strconv.Atoi(columns[j].PrimaryKeyIndex)
+		return a < b
+	})
+	return columns
+}
+
+// PrimaryKeyColumns returns the partition-key columns followed by
+// clustering-columns (column-names as in database).
+func (t *Table) PrimaryKeyColumns() []string {
+	columns := t.PartitionKeyColumns()
+	for _, column := range t.ClusteringColumns() {
+		columns = append(columns, column.Name)
+	}
+	return columns
+}
+
+// clusteringOrder returns the clustering-order (ASC or DESC) of column.
+// The order defaults to ASC.
+func clusteringOrder(column TableColumn) string {
+	if strings.ToUpper(column.PrimaryKeyOrder) == "DESC" {
+		return "DESC"
+	}
+	return "ASC"
+}
@@ -0,0 +1,75 @@
+package cassandra
+
+import (
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Table", func() {
+	Context("primary-key columns are requested", func() {
+		var table *Table
+
+		BeforeEach(func() {
+			session := &mocks.Session{}
+			keyspace, err := NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			table, err = NewTable(session, &TableConfig{
+				Keyspace: keyspace,
+				Name:     "test_table",
+			}, &map[string]TableColumn{
+				"tenant": TableColumn{
+					Name:              "tenant",
+					DataType:          "text",
+					PrimaryKeyIndex:   "0",
+					PartitionKeyIndex: "1",
+				},
+				"yearBucket": TableColumn{
+					Name:              "year_bucket",
+					DataType:          "smallint",
+					PrimaryKeyIndex:   "0",
+					PartitionKeyIndex: "0",
+				},
+				"uuid": TableColumn{
+					Name:            "uuid",
+					DataType:        "uuid",
+					PrimaryKeyIndex: "2",
+				},
+				"timestamp": TableColumn{
+					Name:            "timestamp",
+					DataType:        "timestamp",
+					PrimaryKeyIndex: "1",
+					PrimaryKeyOrder: "desc",
+				},
+				"data": TableColumn{
+					Name:     "data",
+					DataType: "text",
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should return the partition-key columns in partition-key order", func() {
+			Expect(table.PartitionKeyColumns()).To(Equal([]string{"year_bucket", "tenant"}))
+		})
+
+		It("should return the clustering-columns in primary-key order", func() {
+			columns := table.ClusteringColumns()
+			Expect(columns).To(HaveLen(2))
+			Expect(columns[0].Name).To(Equal("timestamp"))
+			Expect(clusteringOrder(columns[0])).To(Equal("DESC"))
+			Expect(columns[1].Name).To(Equal("uuid"))
+			Expect(clusteringOrder(columns[1])).To(Equal("ASC"))
+		})
+
+		It("should return all primary-key columns", func() {
+			Expect(table.PrimaryKeyColumns()).To(Equal(
+				[]string{"year_bucket", "tenant", "timestamp", "uuid"},
+			))
+		})
+	})
+})
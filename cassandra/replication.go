@@ -0,0 +1,186 @@
+package cassandra
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+)
+
+// Replication-classes supported by KeyspaceConfig.
+const (
+	SimpleStrategyClass          = "SimpleStrategy"
+	NetworkTopologyStrategyClass = "NetworkTopologyStrategy"
+)
+
+// Cassandra also accepts replication-classes with their full Java package-name.
+const replicationClassPrefix = "org.apache.cassandra.locator."
+
+// ReplicationConfig is the typed replication-configuration for Keyspace.
+// Use SimpleStrategy or NetworkTopologyStrategy as implementations.
+type ReplicationConfig interface {
+	// Class returns the replication-class as used in database.
+	Class() string
+	// Args returns the replication-arguments, such as datacenter-names
+	// and their replication-factors.
+	Args() map[string]int
+}
+
+// SimpleStrategy places replicas on the next nodes clockwise in the ring,
+// without considering the datacenters. Use only for single-datacenter clusters.
+type SimpleStrategy struct {
+	ReplicationFactor int
+}
+
+// Class returns the replication-class as used in database.
+func (s SimpleStrategy) Class() string {
+	return SimpleStrategyClass
+}
+
+// Args returns the "replication_factor" replication-argument.
+func (s SimpleStrategy) Args() map[string]int {
+	return map[string]int{
+		"replication_factor": s.ReplicationFactor,
+	}
+}
+
+// NetworkTopologyStrategy sets the replication-factor for each datacenter.
+type NetworkTopologyStrategy struct {
+	// Datacenter-names as keys and their replication-factors as values.
+	Datacenters map[string]int
+}
+
+// Class returns the replication-class as used in database.
+func (n NetworkTopologyStrategy) Class() string {
+	return NetworkTopologyStrategyClass
+}
+
+// Args returns the datacenter-names and their replication-factors.
+func (n NetworkTopologyStrategy) Args() map[string]int {
+	return n.Datacenters
+}
+
+// replicationFromConfig returns the replication-class and arguments from
+// provided KeyspaceConfig. The typed KeyspaceConfig.Replication is preferred
+// over ReplicationStrategy and ReplicationStrategyArgs if set.
+func replicationFromConfig(kc KeyspaceConfig) (string, map[string]int) {
+	if kc.Replication != nil {
+		return kc.Replication.Class(), kc.Replication.Args()
+	}
+	return kc.ReplicationStrategy, kc.ReplicationStrategyArgs
+}
+
+// validateReplication checks that the replication-class is known and
+// that the arguments are valid for that class. This doesn't require
+// any database-interaction.
+func validateReplication(class string, args map[string]int) error {
+	for key, value := range args {
+		if value < 0 {
+			return fmt.Errorf(
+				"Replication-factor cannot be negative. Errored Key: \"%s\"", key,
+			)
+		}
+	}
+
+	switch strings.TrimPrefix(class, replicationClassPrefix) {
+	case SimpleStrategyClass:
+		rf, exists := args["replication_factor"]
+		if !exists || len(args) != 1 {
+			return errors.New(
+				"SimpleStrategy requires exactly one argument: \"replication_factor\"",
+			)
+		}
+		if rf == 0 {
+			return errors.New("SimpleStrategy requires a replication_factor above 0")
+		}
+	case NetworkTopologyStrategyClass:
+		if len(args) == 0 {
+			return errors.New(
+				"NetworkTopologyStrategy requires replication-factor for at least one datacenter",
+			)
+		}
+	default:
+		return fmt.Errorf(
+			"Invalid ReplicationStrategy specified: \"%s\". Valid values are: \"%s\" or \"%s\"",
+			class,
+			SimpleStrategyClass,
+			NetworkTopologyStrategyClass,
+		)
+	}
+	return nil
+}
+
+// ValidateReplication checks the replication-config in provided KeyspaceConfig
+// against the cluster-topology, as read from system.local and system.peers tables.
+// An error is returned if a datacenter doesn't exist in cluster.
+// The returned warnings list the replication-factors exceeding the number of
+// nodes available, since writes would fail at consistency-levels such as ALL.
+func ValidateReplication(session driver.SessionI, kc KeyspaceConfig) ([]string, error) {
+	return validateTopology(session, kc, driver.NewIterx)
+}
+
+// validateTopology is #ValidateReplication, with system-tables
+// read using the iterators created by initIterx.
+func validateTopology(
+	session driver.SessionI,
+	kc KeyspaceConfig,
+	initIterx func(q driver.QueryI) driver.IterxI,
+) ([]string, error) {
+	class, args := replicationFromConfig(kc)
+	err := validateReplication(class, args)
+	if err != nil {
+		return nil, err
+	}
+
+	topology, err := clusterTopology(session, initIterx)
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := []string{}
+	if strings.TrimPrefix(class, replicationClassPrefix) == SimpleStrategyClass {
+		nodeCount := 0
+		for _, count := range topology {
+			nodeCount += count
+		}
+		rf := args["replication_factor"]
+		if rf > nodeCount {
+			warnings = append(warnings, fmt.Sprintf(
+				"replication_factor %d exceeds the %d nodes in cluster", rf, nodeCount,
+			))
+		}
+		return warnings, nil
+	}
+
+	for _, dc := range sortedKeys(args) {
+		nodeCount, exists := topology[dc]
+		if !exists {
+			return nil, fmt.Errorf(
+				"Datacenter \"%s\" does not exist in cluster. Available datacenters: %s",
+				dc,
+				strings.Join(sortedKeys(topology), ", "),
+			)
+		}
+		if args[dc] > nodeCount {
+			warnings = append(warnings, fmt.Sprintf(
+				"replication-factor %d for datacenter \"%s\" exceeds its %d nodes",
+				args[dc],
+				dc,
+				nodeCount,
+			))
+		}
+	}
+	return warnings, nil
+}
+
+// sortedKeys returns the keys of provided map in ascending order.
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
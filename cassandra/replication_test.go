@@ -0,0 +1,254 @@
+package cassandra
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	"github.com/TerrexTech/go-commonutils/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Replication", func() {
+	var topologyIterx func(q driver.QueryI) driver.IterxI
+
+	// mockTopology makes system-table queries return the provided nodes.
+	mockTopology := func(local []systemNode, peers []systemNode) {
+		topologyIterx = func(q driver.QueryI) driver.IterxI {
+			return &mocks.Iterx{
+				CqlQuery: q,
+				MockSelect: func(dest interface{}) error {
+					nodes := dest.(*[]systemNode)
+					if strings.Contains(q.Statement(), "system.local") {
+						*nodes = local
+					} else {
+						*nodes = peers
+					}
+					return nil
+				},
+			}
+		}
+	}
+
+	Context("typed replication-config is used", func() {
+		var (
+			outputStr string
+			session   *mocks.Session
+		)
+
+		BeforeEach(func() {
+			session = &mocks.Session{
+				MockQuery: func(stmt string, values ...interface{}) {
+					outputStr = utils.StandardizeSpaces(stmt)
+				},
+			}
+		})
+
+		It("should create query from SimpleStrategy", func() {
+			ks, err := NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 3},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outputStr).To(ContainSubstring(
+				"{ 'class' : 'SimpleStrategy', 'replication_factor': 3 }",
+			))
+			Expect(ks.ReplicationStrategy()).To(Equal(SimpleStrategyClass))
+			Expect(ks.ReplicationStrategyArgs()).To(Equal(map[string]int{
+				"replication_factor": 3,
+			}))
+		})
+
+		It("should create query from NetworkTopologyStrategy", func() {
+			ks, err := NewKeyspace(session, KeyspaceConfig{
+				Name: "test",
+				Replication: NetworkTopologyStrategy{
+					Datacenters: map[string]int{
+						"dc2": 2,
+						"dc1": 1,
+					},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outputStr).To(ContainSubstring(
+				"{ 'class' : 'NetworkTopologyStrategy', 'dc1': 1, 'dc2': 2 }",
+			))
+			Expect(ks.ReplicationStrategy()).To(Equal(NetworkTopologyStrategyClass))
+		})
+
+		It("should set durable_writes if specified", func() {
+			durableWrites := false
+			ks, err := NewKeyspace(session, KeyspaceConfig{
+				Name:          "test",
+				Replication:   SimpleStrategy{ReplicationFactor: 1},
+				DurableWrites: &durableWrites,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outputStr).To(HaveSuffix("} AND durable_writes = false"))
+			Expect(ks.DurableWrites()).To(BeFalse())
+		})
+
+		It("should not set durable_writes if not specified", func() {
+			ks, err := NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outputStr).ToNot(ContainSubstring("durable_writes"))
+			Expect(ks.DurableWrites()).To(BeTrue())
+		})
+	})
+
+	Context("replication-config is invalid", func() {
+		var (
+			isQueryExecuted bool
+			session         *mocks.Session
+		)
+
+		BeforeEach(func() {
+			isQueryExecuted = false
+			session = &mocks.Session{
+				MockQueryExec: func() {
+					isQueryExecuted = true
+				},
+			}
+		})
+
+		It("should return error on unknown strategy-class", func() {
+			_, err := NewKeyspace(session, KeyspaceConfig{
+				Name:                "test",
+				ReplicationStrategy: "NetworkTopolgyStrategy",
+				ReplicationStrategyArgs: map[string]int{
+					"datacenter1": 1,
+				},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(isQueryExecuted).To(BeFalse())
+		})
+
+		It("should accept fully-qualified strategy-class", func() {
+			_, err := NewKeyspace(session, KeyspaceConfig{
+				Name:                "test",
+				ReplicationStrategy: "org.apache.cassandra.locator.SimpleStrategy",
+				ReplicationStrategyArgs: map[string]int{
+					"replication_factor": 1,
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(isQueryExecuted).To(BeTrue())
+		})
+
+		It("should return error if SimpleStrategy has datacenter-args", func() {
+			_, err := NewKeyspace(session, KeyspaceConfig{
+				Name:                "test",
+				ReplicationStrategy: "SimpleStrategy",
+				ReplicationStrategyArgs: map[string]int{
+					"datacenter1": 1,
+				},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return error if NetworkTopologyStrategy has no datacenters", func() {
+			_, err := NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: NetworkTopologyStrategy{},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return error on negative replication-factor", func() {
+			_, err := NewKeyspace(session, KeyspaceConfig{
+				Name: "test",
+				Replication: NetworkTopologyStrategy{
+					Datacenters: map[string]int{
+						"datacenter1": -1,
+					},
+				},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("replication-config is validated against cluster-topology", func() {
+		var session *mocks.Session
+
+		BeforeEach(func() {
+			session = &mocks.Session{}
+			mockTopology(
+				[]systemNode{{DataCenter: "dc1"}},
+				[]systemNode{{DataCenter: "dc1"}, {DataCenter: "dc2"}},
+			)
+		})
+
+		It("should return error if datacenter doesn't exist", func() {
+			_, err := validateTopology(session, KeyspaceConfig{
+				Name: "test",
+				Replication: NetworkTopologyStrategy{
+					Datacenters: map[string]int{
+						"dc3": 1,
+					},
+				},
+			}, topologyIterx)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("dc3"))
+		})
+
+		It("should warn if replication-factor exceeds nodes in datacenter", func() {
+			warnings, err := validateTopology(session, KeyspaceConfig{
+				Name: "test",
+				Replication: NetworkTopologyStrategy{
+					Datacenters: map[string]int{
+						"dc1": 2,
+						"dc2": 3,
+					},
+				},
+			}, topologyIterx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+			Expect(warnings[0]).To(ContainSubstring("dc2"))
+		})
+
+		It("should warn if SimpleStrategy replication-factor exceeds nodes", func() {
+			warnings, err := validateTopology(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 4},
+			}, topologyIterx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(warnings).To(HaveLen(1))
+		})
+
+		It("should store warnings in Keyspace if ValidateTopology is set", func() {
+			ks := &Keyspace{durableWrites: true, initIterx: topologyIterx}
+			_, err := ks.create(session, KeyspaceConfig{
+				Name:             "test",
+				Replication:      SimpleStrategy{ReplicationFactor: 4},
+				ValidateTopology: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ks.ReplicationWarnings()).To(HaveLen(1))
+		})
+
+		It("should return any errors that occur when reading topology", func() {
+			ks := &Keyspace{
+				durableWrites: true,
+				initIterx: func(q driver.QueryI) driver.IterxI {
+					return &mocks.Iterx{
+						CqlQuery: q,
+						MockSelect: func(dest interface{}) error {
+							return errors.New("some-error")
+						},
+					}
+				},
+			}
+			_, err := ks.create(session, KeyspaceConfig{
+				Name:             "test",
+				Replication:      SimpleStrategy{ReplicationFactor: 1},
+				ValidateTopology: true,
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,101 @@
+//go:build go1.18
+// +build go1.18
+
+package cassandra
+
+import (
+	"context"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+)
+
+// Repository wraps a Table, with typed results for rows of struct-type T.
+// The struct-fields are bound to columns by "db" struct-tags, or by
+// snake-cased field-names.
+type Repository[T any] struct {
+	table *Table
+}
+
+// NewRepository creates a Repository for existing table.
+func NewRepository[T any](table *Table) *Repository[T] {
+	return &Repository[T]{
+		table: table,
+	}
+}
+
+// NewRepositoryFromStruct creates the table (if it doesn't exist) using the
+// definition derived from T (see #DefinitionFromStruct), and returns
+// a Repository for it.
+func NewRepositoryFromStruct[T any](
+	session driver.SessionI,
+	tc *TableConfig,
+) (*Repository[T], error) {
+	var row T
+	definition, err := DefinitionFromStruct(row)
+	if err != nil {
+		return nil, err
+	}
+	table, err := NewTable(session, tc, definition)
+	if err != nil {
+		return nil, err
+	}
+	return NewRepository[T](table), nil
+}
+
+// Table returns the wrapped table.
+func (r *Repository[T]) Table() *Table {
+	return r.table
+}
+
+// Insert inserts the row into table.
+func (r *Repository[T]) Insert(row T) error {
+	return <-r.table.AsyncInsert(&row)
+}
+
+// Get gets a single row by its complete primary-key.
+// Returns ErrNotFound if the row doesn't exist. See Table#Get.
+func (r *Repository[T]) Get(
+	ctx context.Context,
+	keyValues map[string]interface{},
+) (T, error) {
+	var row T
+	err := r.table.Get(ctx, keyValues, &row)
+	return row, err
+}
+
+// Find gets the rows matching the SelectParams.
+// The SelectParams.ResultsBind is ignored.
+func (r *Repository[T]) Find(p SelectParams) ([]T, error) {
+	rows := []T{}
+	p.ResultsBind = &rows
+	_, err := r.table.Select(p)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Update updates the rows matching the WHERE clause. See Table#Update.
+func (r *Repository[T]) Update(ctx context.Context, p UpdateParams) error {
+	return r.table.Update(ctx, p)
+}
+
+// Delete deletes the rows or columns matching the WHERE clause.
+// See Table#Delete.
+func (r *Repository[T]) Delete(p DeleteParams) error {
+	return r.table.Delete(p)
+}
+
+// Iterate gets the rows matching the SelectParams row-by-row, calling
+// fn for each row. The SelectParams.ResultsBind is ignored.
+// See Table#Iterate.
+func (r *Repository[T]) Iterate(
+	ctx context.Context,
+	p SelectParams,
+	fn func(row T) error,
+) error {
+	p.ResultsBind = new(T)
+	return r.table.Iterate(ctx, p, func(row interface{}) error {
+		return fn(*row.(*T))
+	})
+}
@@ -0,0 +1,114 @@
+//go:build go1.18
+// +build go1.18
+
+package cassandra
+
+import (
+	"context"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	"github.com/TerrexTech/go-commonutils/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Repository", func() {
+	type user struct {
+		ID   int    `cql:",pk"`
+		Name string `db:"name"`
+	}
+
+	var (
+		queries []string
+		repo    *Repository[user]
+	)
+
+	BeforeEach(func() {
+		queries = []string{}
+		session := &mocks.Session{
+			MockQuery: func(stmt string, values ...interface{}) {
+				queries = append(queries, utils.StandardizeSpaces(stmt))
+			},
+		}
+		keyspace, err := NewKeyspace(session, KeyspaceConfig{
+			Name:        "test",
+			Replication: SimpleStrategy{ReplicationFactor: 1},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		repo, err = NewRepositoryFromStruct[user](session, &TableConfig{
+			Keyspace: keyspace,
+			Name:     "users",
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		repo.Table().initQueryx = func(q driver.QueryI, names []string) driver.QueryxI {
+			return &mocks.Queryx{
+				CqlQuery:    q,
+				ColumnNames: names,
+			}
+		}
+		rows := []user{{1, "a"}, {2, "b"}}
+		repo.Table().initIterx = func(q driver.QueryI) driver.IterxI {
+			scanned := 0
+			return &mocks.Iterx{
+				CqlQuery: q,
+				MockGet: func(dest interface{}) error {
+					*dest.(*user) = rows[0]
+					return nil
+				},
+				MockSelect: func(dest interface{}) error {
+					*dest.(*[]user) = rows
+					return nil
+				},
+				MockStructScan: func(dest interface{}) bool {
+					if scanned == len(rows) {
+						return false
+					}
+					*dest.(*user) = rows[scanned]
+					scanned++
+					return true
+				},
+			}
+		}
+	})
+
+	It("should create table from struct-definition", func() {
+		Expect(queries[1]).To(Equal(
+			"CREATE TABLE IF NOT EXISTS test.users (id bigint, name text, PRIMARY KEY (id))",
+		))
+	})
+
+	It("should return typed results", func() {
+		u, err := repo.Get(context.Background(), map[string]interface{}{"id": 1})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(u).To(Equal(user{1, "a"}))
+
+		users, err := repo.Find(SelectParams{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(users).To(Equal([]user{{1, "a"}, {2, "b"}}))
+
+		iterated := []user{}
+		err = repo.Iterate(context.Background(), SelectParams{}, func(u user) error {
+			iterated = append(iterated, u)
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(iterated).To(Equal(users))
+	})
+
+	It("should write rows", func() {
+		Expect(repo.Insert(user{3, "c"})).To(Succeed())
+		Expect(repo.Update(context.Background(), UpdateParams{
+			ColumnValues: []ColumnComparator{Comparator("id", 3).Eq()},
+			Values:       map[string]interface{}{"name": "d"},
+		})).To(Succeed())
+		Expect(repo.Delete(DeleteParams{
+			ColumnValues: []ColumnComparator{Comparator("id", 3).Eq()},
+		})).To(Succeed())
+
+		Expect(queries[len(queries)-2]).To(Equal("UPDATE test.users SET name=? WHERE id=?"))
+		Expect(queries[len(queries)-1]).To(Equal("DELETE FROM test.users WHERE id=?"))
+	})
+})
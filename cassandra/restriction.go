@@ -0,0 +1,323 @@
+package cassandra
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// restriction is the WHERE clause restriction on a column.
+type restriction struct {
+	operator string
+	// Columns restricted by same tuple-comparator have same non-zero tupleID
+	tupleID int
+}
+
+// restrictions are the column-restrictions from WHERE clause,
+// with column-names (as in database) as keys.
+type restrictions struct {
+	columns map[string]restriction
+	// Partition-key is restricted using TOKEN
+	token bool
+	tuple bool
+}
+
+// isEqualityOperator checks if operator restricts column to specific values.
+func isEqualityOperator(operator string) bool {
+	return operator == "=" || operator == " IN "
+}
+
+// isRangeOperator checks if operator restricts column to a range (slice).
+func isRangeOperator(operator string) bool {
+	switch operator {
+	case "<", "<=", ">", ">=":
+		return true
+	}
+	return false
+}
+
+// restrictions collects the column-restrictions from comparators, and checks
+// that restricted columns exist, tuples only include consecutive
+// clustering-columns, and TOKEN includes all partition-key columns.
+func (t *Table) restrictions(ccs []ColumnComparator) (*restrictions, error) {
+	r := &restrictions{
+		columns: make(map[string]restriction),
+	}
+	clusteringIndex := make(map[string]int)
+	for i, column := range t.ClusteringColumns() {
+		clusteringIndex[column.Name] = i
+	}
+
+	for i, cc := range ccs {
+		if cc.operator == "" {
+			return nil, fmt.Errorf(
+				"No comparison-operator set for column \"%s\"", cc.displayName(),
+			)
+		}
+		if cc.token {
+			partitionKey := t.PartitionKeyColumns()
+			if strings.Join(cc.columns, ",") != strings.Join(partitionKey, ",") {
+				return nil, fmt.Errorf(
+					"TOKEN restriction must include all partition-key columns,"+
+						" in order: (%s). Found: %s",
+					strings.Join(partitionKey, ", "),
+					cc.displayName(),
+				)
+			}
+			r.token = true
+			continue
+		}
+
+		columns := []string{cc.Name}
+		tupleID := 0
+		if cc.columns != nil {
+			columns = cc.columns
+			tupleID = i + 1
+			r.tuple = true
+		}
+		for j, column := range columns {
+			if t.definitionKey(column) == "" {
+				return nil, fmt.Errorf("No column matching %s was found", column)
+			}
+			if tupleID != 0 {
+				index, isClustering := clusteringIndex[column]
+				if !isClustering {
+					return nil, fmt.Errorf(
+						"Tuple restrictions can only include clustering-columns."+
+							" Errored Key: \"%s\"",
+						column,
+					)
+				}
+				if j > 0 && index != clusteringIndex[columns[j-1]]+1 {
+					return nil, fmt.Errorf(
+						"Tuple restriction %s must include consecutive clustering-columns,"+
+							" in primary-key order",
+						cc.displayName(),
+					)
+				}
+			}
+
+			previous, exists := r.columns[column]
+			if exists && !(isRangeOperator(previous.operator) && isRangeOperator(cc.operator)) {
+				return nil, fmt.Errorf(
+					"Column %s has multiple restrictions, only range-restrictions"+
+						" can be combined",
+					column,
+				)
+			}
+			r.columns[column] = restriction{
+				operator: cc.operator,
+				tupleID:  tupleID,
+			}
+		}
+	}
+	return r, nil
+}
+
+// validateSelectRestrictions checks the WHERE clause of SELECT query against
+// primary-key, so the invalid queries are rejected without a database
+// round-trip. Filtering on non-key columns and partial primary-keys is only
+// allowed with ALLOW FILTERING. Secondary-indexes are not considered, use
+// SkipRestrictionValidation when querying indexed columns.
+func (t *Table) validateSelectRestrictions(p SelectParams) error {
+	r, err := t.restrictions(p.ColumnValues)
+	if err != nil || p.AllowFiltering {
+		return err
+	}
+
+	partitionRestricted, err := t.validatePartitionRestrictions(r)
+	if err != nil {
+		return err
+	}
+	if r.token {
+		// Clustering-columns can only be restricted with complete partition-key
+		partitionRestricted = false
+	}
+	err = t.validateClusteringRestrictions(r, partitionRestricted)
+	if err != nil {
+		return err
+	}
+	return t.validateNonKeyRestrictions(r, "SELECT")
+}
+
+// validateWriteRestrictions checks the WHERE clause of UPDATE and DELETE
+// queries against primary-key. The partition-key must be restricted by values,
+// and if allClustering is true, so must be all clustering-columns. Otherwise
+// a clustering-columns prefix can be restricted, with a range-restriction
+// on last restricted column.
+func (t *Table) validateWriteRestrictions(
+	stmt string,
+	ccs []ColumnComparator,
+	allClustering bool,
+) error {
+	if len(ccs) == 0 {
+		return fmt.Errorf("%s requires restrictions on primary-key columns", stmt)
+	}
+	r, err := t.restrictions(ccs)
+	if err != nil {
+		return err
+	}
+	if r.token {
+		return fmt.Errorf("%s does not support TOKEN restrictions", stmt)
+	}
+	if r.tuple {
+		return fmt.Errorf("%s does not support tuple restrictions", stmt)
+	}
+	err = t.validateNonKeyRestrictions(r, stmt)
+	if err != nil {
+		return err
+	}
+
+	for _, column := range t.PartitionKeyColumns() {
+		if _, exists := r.columns[column]; !exists {
+			return fmt.Errorf(
+				"%s requires all partition-key columns restricted, but %s is not",
+				stmt, column,
+			)
+		}
+	}
+	_, err = t.validatePartitionRestrictions(r)
+	if err != nil {
+		return err
+	}
+
+	if allClustering {
+		for _, column := range t.ClusteringColumns() {
+			res, exists := r.columns[column.Name]
+			if !exists || !isEqualityOperator(res.operator) {
+				return fmt.Errorf(
+					"%s requires all clustering-columns restricted by EQ or IN,"+
+						" but %s is not",
+					stmt, column.Name,
+				)
+			}
+		}
+		return nil
+	}
+	return t.validateClusteringRestrictions(r, true)
+}
+
+// validatePartitionRestrictions checks that partition-key columns are only
+// restricted by EQ or IN, and that either all or none of them are restricted.
+// Reports if all columns are restricted.
+func (t *Table) validatePartitionRestrictions(r *restrictions) (bool, error) {
+	var restricted, unrestricted string
+	for _, column := range t.PartitionKeyColumns() {
+		res, exists := r.columns[column]
+		if !exists {
+			if unrestricted == "" {
+				unrestricted = column
+			}
+			continue
+		}
+		if !isEqualityOperator(res.operator) {
+			return false, fmt.Errorf(
+				"Partition-key column %s can only be restricted by EQ or IN,"+
+					" use TokenComparator for range-restrictions",
+				column,
+			)
+		}
+		if restricted == "" {
+			restricted = column
+		}
+	}
+
+	if restricted != "" && r.token {
+		return false, fmt.Errorf(
+			"Partition-key cannot be restricted by both TOKEN and column-values."+
+				" Errored Key: \"%s\"",
+			restricted,
+		)
+	}
+	if restricted != "" && unrestricted != "" {
+		return false, fmt.Errorf(
+			"partition-key column %s restricted but %s is not", restricted, unrestricted,
+		)
+	}
+	return restricted != "" && unrestricted == "", nil
+}
+
+// validateClusteringRestrictions checks that clustering-columns are restricted
+// in primary-key order, and no column following a non-EQ restriction is
+// restricted. The clustering-columns can only be restricted if partitionKey
+// is true (the partition-key is restricted by values).
+func (t *Table) validateClusteringRestrictions(r *restrictions, partitionKey bool) error {
+	var unrestricted string
+	var previous TableColumn
+	var previousRes restriction
+
+	for _, column := range t.ClusteringColumns() {
+		res, exists := r.columns[column.Name]
+		if !exists {
+			if unrestricted == "" {
+				unrestricted = column.Name
+			}
+			continue
+		}
+
+		if !isEqualityOperator(res.operator) && !isRangeOperator(res.operator) {
+			return fmt.Errorf(
+				"clustering column %s can only be restricted by EQ, IN or range-relations"+
+					" (without ALLOW FILTERING). Found: %s",
+				column.Name, strings.TrimSpace(res.operator),
+			)
+		}
+		if !partitionKey {
+			return fmt.Errorf(
+				"clustering column %s restricted but partition-key is not", column.Name,
+			)
+		}
+		if unrestricted != "" {
+			return fmt.Errorf(
+				"clustering column %s restricted but %s is not", column.Name, unrestricted,
+			)
+		}
+		sameTuple := res.tupleID != 0 && res.tupleID == previousRes.tupleID
+		if previous.Name != "" && isRangeOperator(previousRes.operator) && !sameTuple {
+			return fmt.Errorf(
+				"clustering column %s cannot be restricted, since preceding column"+
+					" %s is restricted by a non-EQ relation",
+				column.Name, previous.Name,
+			)
+		}
+		previous = column
+		previousRes = res
+	}
+	return nil
+}
+
+// validateNonKeyRestrictions checks that columns not in primary-key
+// are not restricted.
+func (t *Table) validateNonKeyRestrictions(r *restrictions, stmt string) error {
+	primaryKey := make(map[string]bool)
+	for _, column := range t.PrimaryKeyColumns() {
+		primaryKey[column] = true
+	}
+	for _, column := range sortedRestrictedColumns(r) {
+		if primaryKey[column] {
+			continue
+		}
+		if stmt == "SELECT" {
+			return fmt.Errorf(
+				"column %s is not in primary-key, and can only be restricted"+
+					" with ALLOW FILTERING",
+				column,
+			)
+		}
+		return fmt.Errorf(
+			"%s can only restrict primary-key columns. Errored Key: \"%s\"", stmt, column,
+		)
+	}
+	return nil
+}
+
+// sortedRestrictedColumns returns the restricted columns sorted by name,
+// so the errors are deterministic.
+func sortedRestrictedColumns(r *restrictions) []string {
+	columns := make([]string, 0, len(r.columns))
+	for column := range r.columns {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
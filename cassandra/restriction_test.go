@@ -0,0 +1,254 @@
+package cassandra
+
+import (
+	"context"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Restrictions", func() {
+	var table *Table
+
+	BeforeEach(func() {
+		session := &mocks.Session{}
+		keyspace, err := NewKeyspace(session, KeyspaceConfig{
+			Name:        "test",
+			Replication: SimpleStrategy{ReplicationFactor: 1},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		table, err = NewTable(session, &TableConfig{
+			Keyspace: keyspace,
+			Name:     "test_table",
+		}, &map[string]TableColumn{
+			"tenant": TableColumn{
+				Name:              "tenant",
+				DataType:          "text",
+				PrimaryKeyIndex:   "0",
+				PartitionKeyIndex: "0",
+			},
+			"yearBucket": TableColumn{
+				Name:              "year_bucket",
+				DataType:          "smallint",
+				PrimaryKeyIndex:   "0",
+				PartitionKeyIndex: "1",
+			},
+			"timestamp": TableColumn{
+				Name:            "timestamp",
+				DataType:        "timestamp",
+				PrimaryKeyIndex: "1",
+				PrimaryKeyOrder: "DESC",
+			},
+			"uuid": TableColumn{
+				Name:            "uuid",
+				DataType:        "uuid",
+				PrimaryKeyIndex: "2",
+			},
+			"data": TableColumn{
+				Name:     "data",
+				DataType: "text",
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		table.initIterx = func(q driver.QueryI) driver.IterxI {
+			return &mocks.Iterx{
+				CqlQuery: q,
+			}
+		}
+	})
+
+	partitionKey := []ColumnComparator{
+		Comparator("tenant", "t1").Eq(),
+		Comparator("year_bucket", []int{2017, 2018}).In(),
+	}
+	selectWith := func(ccs ...ColumnComparator) error {
+		_, err := table.Select(SelectParams{
+			ColumnValues: append(append([]ColumnComparator{}, partitionKey...), ccs...),
+			ResultsBind:  &[]map[string]interface{}{},
+		})
+		return err
+	}
+
+	Context("SELECT restrictions are validated", func() {
+		It("should allow restricting clustering-columns in primary-key order", func() {
+			Expect(selectWith()).To(Succeed())
+			Expect(selectWith(Comparator("timestamp", 1).Eq())).To(Succeed())
+			Expect(selectWith(
+				Comparator("timestamp", 1).Eq(),
+				Comparator("uuid", 1).Gt(),
+			)).To(Succeed())
+			Expect(selectWith(
+				Comparator("timestamp", 1).Gt(),
+				Comparator("timestamp", 2).LtOrEq(),
+			)).To(Succeed())
+			Expect(selectWith(
+				TupleComparator([]string{"timestamp", "uuid"}, 1, 2).Gt(),
+			)).To(Succeed())
+		})
+
+		It("should return error if a clustering-column is skipped", func() {
+			err := selectWith(Comparator("uuid", 1).Eq())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(
+				"clustering column uuid restricted but timestamp is not",
+			))
+		})
+
+		It("should return error if column following a range-restriction is restricted", func() {
+			err := selectWith(
+				Comparator("timestamp", 1).Gt(),
+				Comparator("uuid", 1).Eq(),
+			)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return error on range-restrictions on partition-key", func() {
+			_, err := table.Select(SelectParams{
+				ColumnValues: []ColumnComparator{
+					Comparator("tenant", "t1").Eq(),
+					Comparator("year_bucket", 2018).Gt(),
+				},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return error if partition-key is partially restricted", func() {
+			_, err := table.Select(SelectParams{
+				ColumnValues: []ColumnComparator{
+					Comparator("tenant", "t1").Eq(),
+				},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(
+				"partition-key column tenant restricted but year_bucket is not",
+			))
+		})
+
+		It("should validate TOKEN restrictions", func() {
+			_, err := table.Select(SelectParams{
+				ColumnValues: []ColumnComparator{
+					TokenComparator([]string{"tenant", "year_bucket"}, "t1", 2018).Gt(),
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = table.Select(SelectParams{
+				ColumnValues: []ColumnComparator{
+					TokenComparator([]string{"tenant"}, "t1").Gt(),
+				},
+			})
+			Expect(err).To(HaveOccurred())
+
+			err = selectWith(
+				TokenComparator([]string{"tenant", "year_bucket"}, "t1", 2018).Gt(),
+			)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return error on restrictions without partition-key", func() {
+			_, err := table.Select(SelectParams{
+				ColumnValues: []ColumnComparator{
+					Comparator("timestamp", 1).Gt(),
+				},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return error on restricting non-key columns without filtering", func() {
+			err := selectWith(Comparator("data", "d").Eq())
+			Expect(err).To(HaveOccurred())
+
+			err = selectWith(Comparator("invalid", "d").Eq())
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should validate tuple-restrictions", func() {
+			err := selectWith(TupleComparator([]string{"uuid", "timestamp"}, 1, 2).Gt())
+			Expect(err).To(HaveOccurred())
+
+			err = selectWith(TupleComparator([]string{"timestamp", "data"}, 1, 2).Gt())
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should allow filtering with ALLOW FILTERING", func() {
+			_, err := table.Select(SelectParams{
+				ColumnValues: []ColumnComparator{
+					Comparator("uuid", 1).Eq(),
+					Comparator("data", "d").Eq(),
+				},
+				AllowFiltering: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should not validate if SkipRestrictionValidation is set", func() {
+			_, err := table.Select(SelectParams{
+				ColumnValues: []ColumnComparator{
+					Comparator("data", "d").Eq(),
+				},
+				SkipRestrictionValidation: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Context("UPDATE and DELETE restrictions are validated", func() {
+		primaryKey := append(
+			append([]ColumnComparator{}, partitionKey...),
+			Comparator("timestamp", 1).Eq(),
+			Comparator("uuid", 2).Eq(),
+		)
+
+		It("should require all primary-key columns for UPDATE", func() {
+			err := table.Update(context.Background(), UpdateParams{
+				ColumnValues: primaryKey,
+				Values:       map[string]interface{}{"data": "d"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			err = table.Update(context.Background(), UpdateParams{
+				ColumnValues: primaryKey[:3],
+				Values:       map[string]interface{}{"data": "d"},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("uuid is not"))
+		})
+
+		It("should allow deleting rows by clustering-prefix and range", func() {
+			err := table.Delete(DeleteParams{
+				ColumnValues: append(
+					append([]ColumnComparator{}, partitionKey...),
+					Comparator("timestamp", 1).Lt(),
+				),
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			// Columns can only be deleted from specific rows
+			err = table.Delete(DeleteParams{
+				ColumnValues: partitionKey,
+				Columns:      []string{"data"},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return error on restricting non-key columns or TOKEN", func() {
+			err := table.Delete(DeleteParams{
+				ColumnValues: append(
+					append([]ColumnComparator{}, primaryKey...),
+					Comparator("data", "d").Eq(),
+				),
+			})
+			Expect(err).To(HaveOccurred())
+
+			err = table.Delete(DeleteParams{
+				ColumnValues: []ColumnComparator{
+					TokenComparator([]string{"tenant", "year_bucket"}, "t1", 2018).Gt(),
+				},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,297 @@
+package cassandra
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// SchemaFile is the layout of schema-definition files
+// used by #LoadSchemaFile. Sample YAML file:
+//  schemaAgreementTimeout: 30s
+//  keyspaces:
+//    - name: test
+//      replicationStrategy: NetworkTopologyStrategy
+//      replicationStrategyArgs:
+//        datacenter1: 1
+//      tables:
+//        - name: test_table
+//          options:
+//            defaultTimeToLive: 86400
+//          definition:
+//            yearBucket:
+//              name: year_bucket
+//              dataType: smallint
+//              primaryKeyIndex: 0
+//            timestamp:
+//              name: timestamp
+//              dataType: timestamp
+//              primaryKeyIndex: 1
+//              primaryKeyOrder: DESC
+type SchemaFile struct {
+	Keyspaces []KeyspaceSchema `yaml:"keyspaces" json:"keyspaces"`
+	// Duration such as "30s". See KeyspaceConfig.SchemaAgreementTimeout.
+	SchemaAgreementTimeout string `yaml:"schemaAgreementTimeout" json:"schemaAgreementTimeout"`
+}
+
+// KeyspaceSchema defines a keyspace and its tables in SchemaFile.
+type KeyspaceSchema struct {
+	Name                    string         `yaml:"name" json:"name"`
+	ReplicationStrategy     string         `yaml:"replicationStrategy" json:"replicationStrategy"`
+	ReplicationStrategyArgs map[string]int `yaml:"replicationStrategyArgs" json:"replicationStrategyArgs"`
+	DurableWrites           *bool          `yaml:"durableWrites" json:"durableWrites"`
+	Tables                  []TableSchema  `yaml:"tables" json:"tables"`
+}
+
+// TableSchema defines a table in SchemaFile.
+type TableSchema struct {
+	Name       string                  `yaml:"name" json:"name"`
+	Definition map[string]ColumnSchema `yaml:"definition" json:"definition"`
+	Options    *TableOptionsSchema     `yaml:"options" json:"options"`
+}
+
+// ColumnSchema defines a table-column in SchemaFile.
+// See TableColumn for details.
+type ColumnSchema struct {
+	Name              string `yaml:"name" json:"name"`
+	DataType          string `yaml:"dataType" json:"dataType"`
+	PrimaryKeyIndex   *int   `yaml:"primaryKeyIndex" json:"primaryKeyIndex"`
+	PrimaryKeyOrder   string `yaml:"primaryKeyOrder" json:"primaryKeyOrder"`
+	PartitionKeyIndex *int   `yaml:"partitionKeyIndex" json:"partitionKeyIndex"`
+	Static            bool   `yaml:"static" json:"static"`
+}
+
+// TableOptionsSchema defines the table-properties in SchemaFile.
+// See TableOptions for details.
+type TableOptionsSchema struct {
+	BloomFilterFPChance *float64          `yaml:"bloomFilterFPChance" json:"bloomFilterFPChance"`
+	Caching             map[string]string `yaml:"caching" json:"caching"`
+	Comment             string            `yaml:"comment" json:"comment"`
+	Compaction          map[string]string `yaml:"compaction" json:"compaction"`
+	Compression         map[string]string `yaml:"compression" json:"compression"`
+	DefaultTimeToLive   *int              `yaml:"defaultTimeToLive" json:"defaultTimeToLive"`
+	GCGraceSeconds      *int              `yaml:"gcGraceSeconds" json:"gcGraceSeconds"`
+	SpeculativeRetry    string            `yaml:"speculativeRetry" json:"speculativeRetry"`
+	Extra               map[string]string `yaml:"extra" json:"extra"`
+}
+
+// LoadSchemaFile reads the keyspaces and tables from provided YAML (.yaml, .yml)
+// or JSON (.json) file, and creates them in database (if they don't exist)
+// using #NewKeyspace and #NewTable. The complete file is validated before
+// creating anything. See SchemaFile for file-layout.
+// CQL (.cql) files are also supported, see #ParseCQL and ParsedSchema#Apply.
+// Returns the created tables, with "keyspace.table" names as keys.
+func LoadSchemaFile(session driver.SessionI, path string) (map[string]*Table, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.ToLower(filepath.Ext(path)) == ".cql" {
+		ps, err := ParseCQL(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("Error parsing schema-file \"%s\": %s", path, err)
+		}
+		return ps.Apply(session)
+	}
+
+	sf, err := parseSchemaFile(data, filepath.Ext(path))
+	if err != nil {
+		return nil, fmt.Errorf("Error parsing schema-file \"%s\": %s", path, err)
+	}
+	return sf.apply(session)
+}
+
+// parseSchemaFile decodes and validates the schema-file data.
+// The format is determined by file-extension.
+func parseSchemaFile(data []byte, ext string) (*SchemaFile, error) {
+	sf := &SchemaFile{}
+	var err error
+
+	switch strings.ToLower(ext) {
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, sf)
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(sf)
+	default:
+		err = fmt.Errorf(
+			"Unsupported schema-file extension: \"%s\". Valid extensions are:"+
+				" \".yaml\", \".yml\" or \".json\"",
+			ext,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = sf.validate()
+	if err != nil {
+		return nil, err
+	}
+	return sf, nil
+}
+
+// validate checks the keyspace and table configs, without
+// requiring any database-interaction.
+func (sf *SchemaFile) validate() error {
+	if len(sf.Keyspaces) == 0 {
+		return errors.New("No keyspaces defined")
+	}
+	_, err := sf.schemaAgreementTimeout()
+	if err != nil {
+		return err
+	}
+
+	for _, ks := range sf.Keyspaces {
+		err := ValidateName(ks.Name)
+		if err != nil {
+			return err
+		}
+		kc := ks.keyspaceConfig(0)
+		class, args := replicationFromConfig(kc)
+		err = validateReplication(class, args)
+		if err != nil {
+			return fmt.Errorf("Keyspace \"%s\": %s", ks.Name, err)
+		}
+
+		for _, ts := range ks.Tables {
+			err = ts.validate()
+			if err != nil {
+				return fmt.Errorf("Table \"%s.%s\": %s", ks.Name, ts.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// apply creates the keyspaces and tables in database.
+func (sf *SchemaFile) apply(session driver.SessionI) (map[string]*Table, error) {
+	timeout, err := sf.schemaAgreementTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make(map[string]*Table)
+	for _, ks := range sf.Keyspaces {
+		keyspace, err := NewKeyspace(session, ks.keyspaceConfig(timeout))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ts := range ks.Tables {
+			definition := ts.definition()
+			tc := &TableConfig{
+				Keyspace:               keyspace,
+				Name:                   ts.Name,
+				Options:                ts.Options.tableOptions(),
+				SchemaAgreementTimeout: timeout,
+			}
+			table, err := NewTable(session, tc, &definition)
+			if err != nil {
+				return nil, err
+			}
+			tables[fmt.Sprintf("%s.%s", ks.Name, ts.Name)] = table
+		}
+	}
+	return tables, nil
+}
+
+// schemaAgreementTimeout parses the SchemaAgreementTimeout duration.
+func (sf *SchemaFile) schemaAgreementTimeout() (time.Duration, error) {
+	if sf.SchemaAgreementTimeout == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(sf.SchemaAgreementTimeout)
+}
+
+// keyspaceConfig converts KeyspaceSchema to KeyspaceConfig.
+func (ks KeyspaceSchema) keyspaceConfig(timeout time.Duration) KeyspaceConfig {
+	return KeyspaceConfig{
+		Name:                    ks.Name,
+		ReplicationStrategy:     ks.ReplicationStrategy,
+		ReplicationStrategyArgs: ks.ReplicationStrategyArgs,
+		DurableWrites:           ks.DurableWrites,
+		SchemaAgreementTimeout:  timeout,
+	}
+}
+
+// validate checks the table-name, definition and options.
+func (ts TableSchema) validate() error {
+	err := ValidateName(ts.Name)
+	if err != nil {
+		return err
+	}
+	if len(ts.Definition) == 0 {
+		return errors.New("Table Definition not set")
+	}
+
+	definition := ts.definition()
+	for _, column := range definition {
+		err = ValidateIdentifier(column.Name)
+		if err != nil {
+			return err
+		}
+		if column.DataType == "" {
+			return fmt.Errorf("DataType is required. Errored Key: \"%s\"", column.Name)
+		}
+	}
+	_, err = schemaFromDefinition(&definition)
+	if err != nil {
+		return err
+	}
+	return ts.Options.tableOptions().validate()
+}
+
+// definition converts the ColumnSchemas to table-definition.
+// The column-name defaults to definition-key if not specified.
+func (ts TableSchema) definition() map[string]TableColumn {
+	definition := make(map[string]TableColumn, len(ts.Definition))
+	for key, cs := range ts.Definition {
+		column := TableColumn{
+			Name:            cs.Name,
+			DataType:        cs.DataType,
+			PrimaryKeyOrder: cs.PrimaryKeyOrder,
+			Static:          cs.Static,
+		}
+		if column.Name == "" {
+			column.Name = key
+		}
+		if cs.PrimaryKeyIndex != nil {
+			column.PrimaryKeyIndex = strconv.Itoa(*cs.PrimaryKeyIndex)
+		}
+		if cs.PartitionKeyIndex != nil {
+			column.PartitionKeyIndex = strconv.Itoa(*cs.PartitionKeyIndex)
+		}
+		definition[key] = column
+	}
+	return definition
+}
+
+// tableOptions converts TableOptionsSchema to TableOptions.
+func (o *TableOptionsSchema) tableOptions() *TableOptions {
+	if o == nil {
+		return nil
+	}
+	return &TableOptions{
+		BloomFilterFPChance: o.BloomFilterFPChance,
+		Caching:             o.Caching,
+		Comment:             o.Comment,
+		Compaction:          o.Compaction,
+		Compression:         o.Compression,
+		DefaultTimeToLive:   o.DefaultTimeToLive,
+		GCGraceSeconds:      o.GCGraceSeconds,
+		SpeculativeRetry:    o.SpeculativeRetry,
+		Extra:               o.Extra,
+	}
+}
@@ -0,0 +1,170 @@
+package cassandra
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	"github.com/TerrexTech/go-commonutils/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SchemaFile", func() {
+	var (
+		dir     string
+		queries []string
+		session *mocks.Session
+	)
+
+	const yamlSchema = `
+keyspaces:
+  - name: test
+    replicationStrategy: NetworkTopologyStrategy
+    replicationStrategyArgs:
+      datacenter1: 1
+    durableWrites: true
+    tables:
+      - name: test_table
+        options:
+          defaultTimeToLive: 86400
+        definition:
+          yearBucket:
+            name: year_bucket
+            dataType: smallint
+            primaryKeyIndex: 0
+          timestamp:
+            dataType: timestamp
+            primaryKeyIndex: 1
+            primaryKeyOrder: DESC
+          data:
+            dataType: text
+`
+
+	const jsonSchema = `{
+  "keyspaces": [{
+    "name": "test",
+    "replicationStrategy": "SimpleStrategy",
+    "replicationStrategyArgs": {"replication_factor": 1},
+    "tables": [{
+      "name": "test_table",
+      "definition": {
+        "id": {"name": "id", "dataType": "uuid", "primaryKeyIndex": 0}
+      }
+    }]
+  }]
+}`
+
+	writeFile := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		err := ioutil.WriteFile(path, []byte(content), 0644)
+		Expect(err).ToNot(HaveOccurred())
+		return path
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "schemafile")
+		Expect(err).ToNot(HaveOccurred())
+
+		queries = []string{}
+		session = &mocks.Session{
+			MockQuery: func(stmt string, values ...interface{}) {
+				queries = append(queries, utils.StandardizeSpaces(stmt))
+			},
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("should create keyspaces and tables from YAML file", func() {
+		path := writeFile("schema.yaml", yamlSchema)
+		tables, err := LoadSchemaFile(session, path)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(queries).To(HaveLen(2))
+		Expect(queries[0]).To(HavePrefix("CREATE KEYSPACE IF NOT EXISTS test"))
+		Expect(queries[0]).To(HaveSuffix("AND durable_writes = true"))
+		Expect(queries[1]).To(HavePrefix("CREATE TABLE IF NOT EXISTS test.test_table"))
+		Expect(queries[1]).To(HaveSuffix(
+			"WITH CLUSTERING ORDER BY (timestamp DESC) AND default_time_to_live = 86400",
+		))
+
+		Expect(tables).To(HaveKey("test.test_table"))
+		table := tables["test.test_table"]
+		Expect(table.Keyspace().Name()).To(Equal("test"))
+		// Column-name defaults to definition-key
+		Expect(table.Columns()).To(Equal([]string{"data", "timestamp", "year_bucket"}))
+	})
+
+	It("should create keyspaces and tables from JSON file", func() {
+		path := writeFile("schema.json", jsonSchema)
+		tables, err := LoadSchemaFile(session, path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(queries).To(HaveLen(2))
+		Expect(tables["test.test_table"].Columns()).To(Equal([]string{"id"}))
+	})
+
+	It("should create keyspaces and tables from CQL file", func() {
+		path := writeFile("schema.cql", `
+CREATE KEYSPACE test WITH replication = {
+  'class': 'SimpleStrategy', 'replication_factor': 1
+};
+CREATE TABLE test.test_table (id uuid PRIMARY KEY, data text);
+`)
+		tables, err := LoadSchemaFile(session, path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(queries).To(HaveLen(2))
+		Expect(tables["test.test_table"].Columns()).To(Equal([]string{"data", "id"}))
+	})
+
+	It("should return error on unsupported file-extensions", func() {
+		path := writeFile("schema.txt", yamlSchema)
+		_, err := LoadSchemaFile(session, path)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return error on unknown fields", func() {
+		path := writeFile("schema.yaml", strings.Replace(
+			yamlSchema, "dataType: text", "datatype: text", 1,
+		))
+		_, err := LoadSchemaFile(session, path)
+		Expect(err).To(HaveOccurred())
+		Expect(queries).To(BeEmpty())
+	})
+
+	It("should validate the complete file before creating anything", func() {
+		invalidTable := `
+      - name: invalid_table
+        definition:
+          id:
+            dataType: uuid
+            primaryKeyIndex: 1
+`
+		path := writeFile("schema.yaml", yamlSchema+invalidTable)
+		_, err := LoadSchemaFile(session, path)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("test.invalid_table"))
+		Expect(queries).To(BeEmpty())
+	})
+
+	It("should return error on invalid replication-config", func() {
+		path := writeFile("schema.yaml", strings.Replace(
+			yamlSchema, "NetworkTopologyStrategy", "NetworkStrategy", 1,
+		))
+		_, err := LoadSchemaFile(session, path)
+		Expect(err).To(HaveOccurred())
+		Expect(queries).To(BeEmpty())
+	})
+
+	It("should return any errors that occur when creating tables", func() {
+		session.MockQueryExecError = "some-error"
+		path := writeFile("schema.yaml", yamlSchema)
+		_, err := LoadSchemaFile(session, path)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,156 @@
+package cassandra
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SelectExpression is an expression in SELECT clause, such as a column,
+// aggregate or function-call. Use #As to alias the expression, so the
+// results can be bound to struct-fields by alias.
+// Sample:
+//  SelectParams{
+//    SelectExpressions: []SelectExpression{
+//      SelectColumn("data"),
+//      WriteTime("data").As("written_at"),
+//    },
+//  }
+type SelectExpression struct {
+	cql string
+	// Column-names used in expression
+	columns []string
+	alias   string
+	err     error
+}
+
+// SelectColumn creates a SelectExpression for column.
+func SelectColumn(col string) SelectExpression {
+	return SelectExpression{
+		cql:     QuoteIdentifier(col),
+		columns: []string{col},
+	}
+}
+
+// CountAll creates a COUNT(*) aggregate.
+func CountAll() SelectExpression {
+	return SelectExpression{
+		cql: "COUNT(*)",
+	}
+}
+
+// Count creates a COUNT aggregate, which counts non-null values of column.
+func Count(col string) SelectExpression {
+	return selectFunction("COUNT", col)
+}
+
+// Min creates a MIN aggregate for column.
+func Min(col string) SelectExpression {
+	return selectFunction("MIN", col)
+}
+
+// Max creates a MAX aggregate for column.
+func Max(col string) SelectExpression {
+	return selectFunction("MAX", col)
+}
+
+// Sum creates a SUM aggregate for column.
+func Sum(col string) SelectExpression {
+	return selectFunction("SUM", col)
+}
+
+// Avg creates an AVG aggregate for column.
+func Avg(col string) SelectExpression {
+	return selectFunction("AVG", col)
+}
+
+// WriteTime creates a WRITETIME function-call, which returns the
+// write-timestamp (in microseconds) of column-value.
+func WriteTime(col string) SelectExpression {
+	return selectFunction("WRITETIME", col)
+}
+
+// TTL creates a TTL function-call, which returns the remaining
+// time-to-live (in seconds) of column-value.
+func TTL(col string) SelectExpression {
+	return selectFunction("TTL", col)
+}
+
+// Token creates a TOKEN function-call on the provided partition-key columns.
+func Token(cols ...string) SelectExpression {
+	if len(cols) == 0 {
+		return SelectExpression{
+			err: errors.New("TOKEN requires at least one column"),
+		}
+	}
+	return selectFunction("TOKEN", cols...)
+}
+
+// ToJSON creates a toJson function-call, which returns the
+// column-value as JSON-encoded string.
+func ToJSON(col string) SelectExpression {
+	return selectFunction("toJson", col)
+}
+
+// Cast creates a CAST expression, which converts the result of
+// expression to the provided native data-type.
+func Cast(expr SelectExpression, dataType string) SelectExpression {
+	cast := SelectExpression{
+		cql:     fmt.Sprintf("CAST(%s AS %s)", expr.cql, dataType),
+		columns: expr.columns,
+		err:     expr.err,
+	}
+	if cast.err == nil && !identifierRgx.MatchString(dataType) {
+		cast.err = fmt.Errorf("Invalid data-type for CAST: \"%s\"", dataType)
+	}
+	return cast
+}
+
+// As aliases the expression. The alias is the column-name in
+// results, and can be used to bind the value to struct-field.
+func (se SelectExpression) As(alias string) SelectExpression {
+	se.alias = alias
+	return se
+}
+
+// Alias returns the alias, as set using #As.
+func (se SelectExpression) Alias() string {
+	return se.alias
+}
+
+// toCql returns the expression as used in SELECT clause.
+func (se SelectExpression) toCql() string {
+	if se.alias == "" {
+		return se.cql
+	}
+	return fmt.Sprintf("%s AS %s", se.cql, QuoteIdentifier(se.alias))
+}
+
+// validate checks that expression is well-formed, and only uses
+// existing table-columns.
+func (se SelectExpression) validate(t *Table) error {
+	if se.err != nil {
+		return se.err
+	}
+	if se.alias != "" {
+		err := ValidateIdentifier(se.alias)
+		if err != nil {
+			return err
+		}
+	}
+	for _, column := range se.columns {
+		if t.definitionKey(column) == "" {
+			return fmt.Errorf("No column matching %s was found", column)
+		}
+	}
+	return nil
+}
+
+// selectFunction creates a SelectExpression calling the function
+// on provided columns.
+func selectFunction(function string, cols ...string) SelectExpression {
+	return SelectExpression{
+		cql:     fmt.Sprintf("%s(%s)", function, strings.Join(quoteIdentifiers(cols), ",")),
+		columns: cols,
+	}
+}
@@ -0,0 +1,37 @@
+package cassandra
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SelectExpression", func() {
+	It("should create aggregates", func() {
+		Expect(CountAll().toCql()).To(Equal("COUNT(*)"))
+		Expect(Count("data").toCql()).To(Equal("COUNT(data)"))
+		Expect(Min("value").toCql()).To(Equal("MIN(value)"))
+		Expect(Max("value").toCql()).To(Equal("MAX(value)"))
+		Expect(Sum("value").toCql()).To(Equal("SUM(value)"))
+		Expect(Avg("value").toCql()).To(Equal("AVG(value)"))
+	})
+
+	It("should create function-calls", func() {
+		Expect(WriteTime("data").toCql()).To(Equal("WRITETIME(data)"))
+		Expect(TTL("data").toCql()).To(Equal("TTL(data)"))
+		Expect(Token("tenant", "year_bucket").toCql()).To(Equal("TOKEN(tenant,year_bucket)"))
+		Expect(ToJSON("data").toCql()).To(Equal("toJson(data)"))
+		Expect(Cast(Avg("value"), "float").toCql()).To(Equal("CAST(AVG(value) AS float)"))
+	})
+
+	It("should quote column-names and aliases", func() {
+		se := WriteTime("order").As("writtenAt")
+		Expect(se.Alias()).To(Equal("writtenAt"))
+		Expect(se.toCql()).To(Equal(`WRITETIME("order") AS "writtenAt"`))
+		Expect(SelectColumn("data").As("value").toCql()).To(Equal("data AS value"))
+	})
+
+	It("should return error on invalid expressions", func() {
+		Expect(Token().err).To(HaveOccurred())
+		Expect(Cast(SelectColumn("data"), "text; DROP").err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,235 @@
+package cassandra
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	cql "github.com/gocql/gocql"
+	cqlx "github.com/scylladb/gocqlx"
+)
+
+// DefinitionFromStruct derives the table-definition from struct-fields of
+// v (a struct or pointer to struct). The column-names are taken from "db"
+// struct-tags, or are the snake-cased field-names (same as for binding
+// struct-fields). The schema is defined by "cql" struct-tags, as:
+//  cql:"[data-type][,pk[=N]][,ck=N][,desc][,static]"
+// where pk marks the partition-key columns (with N being the position in
+// composite partition-key), and ck=N marks the clustering-columns (with N
+// being the primary-key index, starting at 1). The data-type is derived
+// from field-type if not specified. Fields with "-" tags are skipped.
+// The definition-keys are the lower-camel-cased field-names.
+// Sample:
+//  type event struct {
+//    Tenant    string    `cql:",pk=0"`
+//    Bucket    int16     `db:"year_bucket" cql:"smallint,pk=1"`
+//    Timestamp time.Time `cql:",ck=1,desc"`
+//    Data      string
+//  }
+func DefinitionFromStruct(v interface{}) (*map[string]TableColumn, error) {
+	structType := reflect.TypeOf(v)
+	if structType != nil && structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType == nil || structType.Kind() != reflect.Struct {
+		return nil, errors.New("DefinitionFromStruct requires a struct")
+	}
+
+	// Column-names are same as the names for binding struct-fields
+	columnNames := make(map[int]string)
+	for name, fi := range cqlx.DefaultMapper.TypeMap(structType).Names {
+		if len(fi.Index) == 1 {
+			columnNames[fi.Index[0]] = name
+		}
+	}
+
+	definition := make(map[string]TableColumn)
+	partitionKeys := 0
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		dbTag := field.Tag.Get("db")
+		cqlTag := field.Tag.Get("cql")
+		if field.PkgPath != "" || dbTag == "-" || cqlTag == "-" {
+			continue
+		}
+
+		column, err := columnFromField(field, columnNames[i])
+		if err != nil {
+			return nil, err
+		}
+		if column.PrimaryKeyIndex == "0" {
+			partitionKeys++
+		}
+		key := lowerFirst(field.Name)
+		definition[key] = column
+	}
+	if partitionKeys == 0 {
+		return nil, errors.New("Struct must have at least one partition-key (pk) field")
+	}
+
+	// PartitionKeyIndex is required for composite partition-keys
+	for key, column := range definition {
+		if column.PrimaryKeyIndex != "0" {
+			continue
+		}
+		if partitionKeys == 1 {
+			column.PartitionKeyIndex = ""
+			definition[key] = column
+		} else if column.PartitionKeyIndex == "" {
+			return nil, fmt.Errorf(
+				"Composite partition-key requires pk=N for all pk fields. Errored Key: \"%s\"",
+				column.Name,
+			)
+		}
+	}
+	return &definition, nil
+}
+
+// columnFromField creates the TableColumn (named columnName) from struct-field
+// and its tags.
+func columnFromField(field reflect.StructField, columnName string) (TableColumn, error) {
+	column := TableColumn{
+		Name: columnName,
+	}
+
+	options := splitTagOptions(field.Tag.Get("cql"))
+	column.DataType = strings.TrimSpace(options[0])
+	for _, option := range options[1:] {
+		option = strings.TrimSpace(option)
+		name, value := option, ""
+		if eq := strings.Index(option, "="); eq != -1 {
+			name, value = option[:eq], option[eq+1:]
+		}
+
+		switch name {
+		case "pk":
+			column.PrimaryKeyIndex = "0"
+			column.PartitionKeyIndex = value
+		case "ck":
+			if index, err := strconv.Atoi(value); err != nil || index < 1 {
+				return column, fmt.Errorf(
+					"ck requires a primary-key index (starting at 1). Errored Key: \"%s\"",
+					column.Name,
+				)
+			}
+			column.PrimaryKeyIndex = value
+		case "asc", "desc":
+			column.PrimaryKeyOrder = strings.ToUpper(name)
+		case "static":
+			column.Static = true
+		default:
+			return column, fmt.Errorf(
+				"Invalid cql struct-tag option \"%s\". Errored Key: \"%s\"",
+				option, column.Name,
+			)
+		}
+	}
+
+	if column.DataType == "" {
+		dataType, err := cqlDataType(field.Type)
+		if err != nil {
+			return column, fmt.Errorf("%s. Errored Key: \"%s\"", err.Error(), column.Name)
+		}
+		column.DataType = dataType
+	}
+	return column, nil
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	uuidType = reflect.TypeOf(cql.UUID{})
+)
+
+// cqlDataType returns the CQL data-type for Go type.
+func cqlDataType(t reflect.Type) (string, error) {
+	switch t {
+	case timeType:
+		return "timestamp", nil
+	case uuidType:
+		return "uuid", nil
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return cqlDataType(t.Elem())
+	case reflect.String:
+		return "text", nil
+	case reflect.Bool:
+		return "boolean", nil
+	case reflect.Int8:
+		return "tinyint", nil
+	case reflect.Int16, reflect.Uint8:
+		return "smallint", nil
+	case reflect.Int32, reflect.Uint16:
+		return "int", nil
+	case reflect.Int, reflect.Int64, reflect.Uint32:
+		return "bigint", nil
+	case reflect.Float32:
+		return "float", nil
+	case reflect.Float64:
+		return "double", nil
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "blob", nil
+		}
+		elemType, err := cqlDataType(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("list<%s>", elemType), nil
+	case reflect.Map:
+		keyType, err := cqlDataType(t.Key())
+		if err != nil {
+			return "", err
+		}
+		valueType, err := cqlDataType(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("map<%s, %s>", keyType, valueType), nil
+	}
+	return "", fmt.Errorf("No CQL data-type for %s, specify it in cql struct-tag", t)
+}
+
+// splitTagOptions splits the struct-tag on commas,
+// except the ones in data-type parameters (such as "map<text, int>").
+func splitTagOptions(tag string) []string {
+	options := []string{}
+	depth := 0
+	start := 0
+	for i, c := range tag {
+		switch c {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case ',':
+			if depth == 0 {
+				options = append(options, tag[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(options, tag[start:])
+}
+
+// lowerFirst returns the string with leading upper-case letters in
+// lower-case, keeping the last one if followed by a lower-case letter
+// (such as "ID" to "id", and "URLPath" to "urlPath").
+func lowerFirst(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsUpper(r) {
+			break
+		}
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(r)
+	}
+	return string(runes)
+}
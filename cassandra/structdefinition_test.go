@@ -0,0 +1,116 @@
+package cassandra
+
+import (
+	"time"
+
+	cql "github.com/gocql/gocql"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DefinitionFromStruct", func() {
+	It("should derive table-definition from struct", func() {
+		type event struct {
+			Tenant    string           `cql:",pk=0"`
+			Bucket    int16            `db:"year_bucket" cql:"smallint,pk=1"`
+			Timestamp time.Time        `cql:",ck=1,desc"`
+			ID        cql.UUID         `cql:"timeuuid,ck=2"`
+			Name      string           `cql:",static"`
+			Tags      map[string][]int `cql:"map<text, frozen<list<int>>>"`
+			Data      []byte
+			Count     *int64
+			UserID    string
+			Ignored   string `db:"-"`
+			internal  string
+		}
+
+		definition, err := DefinitionFromStruct(&event{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*definition).To(Equal(map[string]TableColumn{
+			"tenant": TableColumn{
+				Name:              "tenant",
+				DataType:          "text",
+				PrimaryKeyIndex:   "0",
+				PartitionKeyIndex: "0",
+			},
+			"bucket": TableColumn{
+				Name:              "year_bucket",
+				DataType:          "smallint",
+				PrimaryKeyIndex:   "0",
+				PartitionKeyIndex: "1",
+			},
+			"timestamp": TableColumn{
+				Name:            "timestamp",
+				DataType:        "timestamp",
+				PrimaryKeyIndex: "1",
+				PrimaryKeyOrder: "DESC",
+			},
+			"id": TableColumn{
+				Name:            "id",
+				DataType:        "timeuuid",
+				PrimaryKeyIndex: "2",
+			},
+			"name": TableColumn{
+				Name:     "name",
+				DataType: "text",
+				Static:   true,
+			},
+			"tags": TableColumn{
+				Name:     "tags",
+				DataType: "map<text, frozen<list<int>>>",
+			},
+			"data": TableColumn{
+				Name:     "data",
+				DataType: "blob",
+			},
+			"count": TableColumn{
+				Name:     "count",
+				DataType: "bigint",
+			},
+			"userID": TableColumn{
+				Name:     "user_id",
+				DataType: "text",
+			},
+		}))
+	})
+
+	It("should lower-case the leading acronyms in definition-keys", func() {
+		Expect(lowerFirst("ID")).To(Equal("id"))
+		Expect(lowerFirst("UserID")).To(Equal("userID"))
+		Expect(lowerFirst("URLPath")).To(Equal("urlPath"))
+	})
+
+	It("should return error on invalid struct-tags", func() {
+		type noPartitionKey struct {
+			ID string
+		}
+		type incompletePartitionKey struct {
+			A string `cql:",pk=0"`
+			B string `cql:",pk"`
+		}
+		type invalidOption struct {
+			ID string `cql:",pk,unique"`
+		}
+		type invalidClustering struct {
+			ID string `cql:",pk"`
+			TS string `cql:",ck"`
+		}
+		type unsupportedType struct {
+			ID   string `cql:",pk"`
+			Data struct{}
+		}
+
+		for _, v := range []interface{}{
+			noPartitionKey{},
+			incompletePartitionKey{},
+			invalidOption{},
+			invalidClustering{},
+			unsupportedType{},
+			"invalid",
+			nil,
+		} {
+			_, err := DefinitionFromStruct(v)
+			Expect(err).To(HaveOccurred())
+		}
+	})
+})
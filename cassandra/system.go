@@ -0,0 +1,191 @@
+package cassandra
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+	cql "github.com/gocql/gocql"
+)
+
+// systemNode represents a node-row from system.local or system.peers tables.
+type systemNode struct {
+	DataCenter string `db:"data_center"`
+}
+
+// systemIterx creates the iterator used for reading system-tables,
+// using driver.NewIterx if initIterx is nil.
+func systemIterx(
+	initIterx func(q driver.QueryI) driver.IterxI,
+	q driver.QueryI,
+) driver.IterxI {
+	if initIterx == nil {
+		return driver.NewIterx(q)
+	}
+	return initIterx(q)
+}
+
+// clusterTopology returns the number of nodes in each datacenter, as seen
+// by the node the session is connected to. The map-keys are datacenter-names
+// and values are the number of nodes in that datacenter.
+func clusterTopology(
+	session driver.SessionI,
+	initIterx func(q driver.QueryI) driver.IterxI,
+) (map[string]int, error) {
+	topology := make(map[string]int)
+
+	for _, table := range []string{"system.local", "system.peers"} {
+		nodes := []systemNode{}
+		q := session.Query(fmt.Sprintf("SELECT data_center FROM %s", table))
+
+		i := systemIterx(initIterx, q)
+		err := i.Select(&nodes)
+		if err != nil {
+			i.Close()
+			return nil, err
+		}
+		err = i.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, node := range nodes {
+			topology[node.DataCenter]++
+		}
+	}
+	return topology, nil
+}
+
+// schemaAgreementInterval is the delay between subsequent schema-version
+// checks when waiting for schema-agreement.
+var schemaAgreementInterval = 200 * time.Millisecond
+
+// SchemaAgreementError is returned when the nodes in cluster don't agree
+// on schema-version within the specified timeout.
+type SchemaAgreementError struct {
+	// Schema-version of the node the session is connected to.
+	LocalVersion string
+	// Hosts disagreeing with LocalVersion as keys,
+	// and their schema-versions as values.
+	Hosts   map[string]string
+	Timeout time.Duration
+}
+
+func (e *SchemaAgreementError) Error() string {
+	hosts := make([]string, 0, len(e.Hosts))
+	for host, version := range e.Hosts {
+		hosts = append(hosts, fmt.Sprintf("%s (%s)", host, version))
+	}
+	sort.Strings(hosts)
+
+	return fmt.Sprintf(
+		"Schema-agreement not reached within %s. Local schema-version: %s."+
+			" Disagreeing hosts: %s",
+		e.Timeout,
+		e.LocalVersion,
+		strings.Join(hosts, ", "),
+	)
+}
+
+// schemaVersionRow represents a node-row from system.local or
+// system.peers tables when reading schema-versions.
+type schemaVersionRow struct {
+	Host          string   `db:"host"`
+	SchemaVersion cql.UUID `db:"schema_version"`
+}
+
+// WaitForSchemaAgreement blocks until all nodes in cluster have the same
+// schema-version as the node the session is connected to, by comparing
+// schema_version across system.local and system.peers tables.
+// A *SchemaAgreementError is returned if the agreement isn't reached
+// within provided timeout.
+func WaitForSchemaAgreement(session driver.SessionI, timeout time.Duration) error {
+	return waitForSchemaAgreement(session, timeout, driver.NewIterx)
+}
+
+// waitForSchemaAgreement is #WaitForSchemaAgreement, with system-tables
+// read using the iterators created by initIterx.
+func waitForSchemaAgreement(
+	session driver.SessionI,
+	timeout time.Duration,
+	initIterx func(q driver.QueryI) driver.IterxI,
+) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		localVersion, disagreeingHosts, err := schemaVersions(session, initIterx)
+		if err != nil {
+			return err
+		}
+		if len(disagreeingHosts) == 0 {
+			return nil
+		}
+		if time.Now().Add(schemaAgreementInterval).After(deadline) {
+			return &SchemaAgreementError{
+				LocalVersion: localVersion,
+				Hosts:        disagreeingHosts,
+				Timeout:      timeout,
+			}
+		}
+		time.Sleep(schemaAgreementInterval)
+	}
+}
+
+// schemaVersions returns the local schema-version, and the hosts (along with
+// their schema-versions) which don't agree with local schema-version.
+// Peers with unknown schema-version (such as the ones that are down) are ignored.
+func schemaVersions(
+	session driver.SessionI,
+	initIterx func(q driver.QueryI) driver.IterxI,
+) (string, map[string]string, error) {
+	queries := []string{
+		"SELECT rpc_address AS host, schema_version FROM system.local",
+		"SELECT peer AS host, schema_version FROM system.peers",
+	}
+
+	rows := []schemaVersionRow{}
+	for _, stmt := range queries {
+		result := []schemaVersionRow{}
+		i := systemIterx(initIterx, session.Query(stmt))
+		err := i.Select(&result)
+		if err != nil {
+			i.Close()
+			return "", nil, err
+		}
+		err = i.Close()
+		if err != nil {
+			return "", nil, err
+		}
+		if len(rows) == 0 && len(result) == 0 {
+			return "", nil, errors.New("No schema-version found in system.local")
+		}
+		rows = append(rows, result...)
+	}
+
+	localVersion := rows[0].SchemaVersion
+	disagreeingHosts := make(map[string]string)
+	for _, row := range rows[1:] {
+		if row.SchemaVersion == (cql.UUID{}) {
+			continue
+		}
+		if row.SchemaVersion != localVersion {
+			disagreeingHosts[row.Host] = row.SchemaVersion.String()
+		}
+	}
+	return localVersion.String(), disagreeingHosts, nil
+}
+
+// awaitSchemaAgreement waits for schema-agreement if the provided
+// timeout is greater than zero. This is used after DDL operations.
+func awaitSchemaAgreement(
+	session driver.SessionI,
+	timeout time.Duration,
+	initIterx func(q driver.QueryI) driver.IterxI,
+) error {
+	if timeout <= 0 {
+		return nil
+	}
+	return waitForSchemaAgreement(session, timeout, initIterx)
+}
@@ -0,0 +1,161 @@
+package cassandra
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	cql "github.com/gocql/gocql"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SchemaAgreement", func() {
+	var (
+		localVersion cql.UUID
+		peerVersions []schemaVersionRow
+		selectCount  int
+		versionIterx func(q driver.QueryI) driver.IterxI
+	)
+
+	BeforeEach(func() {
+		localVersion, _ = cql.RandomUUID()
+		peerVersions = []schemaVersionRow{
+			schemaVersionRow{
+				Host:          "10.0.0.2",
+				SchemaVersion: localVersion,
+			},
+		}
+		selectCount = 0
+		schemaAgreementInterval = time.Millisecond
+
+		versionIterx = func(q driver.QueryI) driver.IterxI {
+			return &mocks.Iterx{
+				CqlQuery: q,
+				MockSelect: func(dest interface{}) error {
+					selectCount++
+					rows := dest.(*[]schemaVersionRow)
+					if strings.Contains(q.Statement(), "system.local") {
+						*rows = []schemaVersionRow{
+							schemaVersionRow{
+								Host:          "10.0.0.1",
+								SchemaVersion: localVersion,
+							},
+						}
+					} else {
+						*rows = peerVersions
+					}
+					return nil
+				},
+			}
+		}
+	})
+
+	AfterEach(func() {
+		schemaAgreementInterval = 200 * time.Millisecond
+	})
+
+	It("should return nil if all nodes agree on schema-version", func() {
+		err := waitForSchemaAgreement(&mocks.Session{}, time.Second, versionIterx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(selectCount).To(Equal(2))
+	})
+
+	It("should ignore peers with unknown schema-version", func() {
+		peerVersions = append(peerVersions, schemaVersionRow{
+			Host: "10.0.0.3",
+		})
+		err := waitForSchemaAgreement(&mocks.Session{}, time.Second, versionIterx)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should return SchemaAgreementError listing disagreeing hosts", func() {
+		peerVersion, _ := cql.RandomUUID()
+		peerVersions = append(peerVersions, schemaVersionRow{
+			Host:          "10.0.0.3",
+			SchemaVersion: peerVersion,
+		})
+		err := waitForSchemaAgreement(&mocks.Session{}, 10*time.Millisecond, versionIterx)
+		Expect(err).To(HaveOccurred())
+
+		agreementErr, ok := err.(*SchemaAgreementError)
+		Expect(ok).To(BeTrue())
+		Expect(agreementErr.LocalVersion).To(Equal(localVersion.String()))
+		Expect(agreementErr.Hosts).To(Equal(map[string]string{
+			"10.0.0.3": peerVersion.String(),
+		}))
+		Expect(err.Error()).To(ContainSubstring("10.0.0.3"))
+	})
+
+	It("should wait for DDL operations if SchemaAgreementTimeout is set", func() {
+		keyspace := &Keyspace{durableWrites: true, initIterx: versionIterx}
+		_, err := keyspace.create(&mocks.Session{}, KeyspaceConfig{
+			Name:                   "test",
+			Replication:            SimpleStrategy{ReplicationFactor: 1},
+			SchemaAgreementTimeout: time.Second,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(selectCount).To(Equal(2))
+
+		_, err = NewTable(
+			&mocks.Session{},
+			&TableConfig{
+				Keyspace:               keyspace,
+				Name:                   "test_table",
+				SchemaAgreementTimeout: time.Second,
+			},
+			&map[string]TableColumn{
+				"text1": TableColumn{
+					Name:            "textcol1",
+					DataType:        "text",
+					PrimaryKeyIndex: "0",
+				},
+			},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(selectCount).To(Equal(4))
+	})
+
+	It("should wait for DDL operations of ParsedSchema#Apply", func() {
+		schema, err := ParseCQL(`
+CREATE KEYSPACE test WITH replication = {
+  'class': 'SimpleStrategy', 'replication_factor': 1
+};
+CREATE TYPE test.address (street text);
+CREATE TABLE test.users (id uuid PRIMARY KEY, home frozen<address>);
+`)
+		Expect(err).ToNot(HaveOccurred())
+		schema.SchemaAgreementTimeout = time.Second
+		schema.initIterx = versionIterx
+
+		_, err = schema.Apply(&mocks.Session{})
+		Expect(err).ToNot(HaveOccurred())
+		// Keyspace, type and table, with two selects each
+		Expect(selectCount).To(Equal(6))
+	})
+
+	It("should not wait for DDL operations if SchemaAgreementTimeout is not set", func() {
+		keyspace := &Keyspace{durableWrites: true, initIterx: versionIterx}
+		_, err := keyspace.create(&mocks.Session{}, KeyspaceConfig{
+			Name:        "test",
+			Replication: SimpleStrategy{ReplicationFactor: 1},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(selectCount).To(Equal(0))
+	})
+
+	It("should return any errors that occur when reading schema-versions", func() {
+		versionIterx = func(q driver.QueryI) driver.IterxI {
+			return &mocks.Iterx{
+				CqlQuery: q,
+				MockClose: func() error {
+					return errors.New("some-error")
+				},
+			}
+		}
+		err := waitForSchemaAgreement(&mocks.Session{}, time.Second, versionIterx)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -2,7 +2,10 @@ package cassandra
 
 import (
 	"fmt"
+	"sort"
+	"time"
 
+	cql "github.com/gocql/gocql"
 	"github.com/scylladb/gocqlx/qb"
 
 	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
@@ -12,6 +15,23 @@ import (
 type TableConfig struct {
 	Keyspace *Keyspace
 	Name     string
+	// Table-properties used when creating table
+	Options *TableOptions
+	// If greater than zero, the DDL operations wait (until this timeout)
+	// for all nodes to agree on schema-version. See #WaitForSchemaAgreement.
+	SchemaAgreementTimeout time.Duration
+	// If set, the cursors returned by #SelectPage are HMAC-signed using
+	// this key, and the tampered cursors are rejected.
+	CursorKey []byte
+	// Time-bucketing of partitions, see Bucketing
+	Bucketing *Bucketing
+	// Consistency for serial-phase of lightweight-transactions,
+	// defaults to gocql.Serial. See #CompareAndSet.
+	SerialConsistency cql.SerialConsistency
+	// Integer column storing the row-version for optimistic locking.
+	// If set, #Update and #UpdateStruct require the expected version, and
+	// increment it using a lightweight-transaction. See ErrConcurrentModification.
+	VersionColumn string
 }
 
 // TableColumn represents column-definition for database.
@@ -20,13 +40,25 @@ type TableColumn struct {
 	DataType        string
 	PrimaryKeyIndex string
 	PrimaryKeyOrder string
+	// Position of column in composite partition-key. All partition-key
+	// columns (the ones with PrimaryKeyIndex "0") must specify this to
+	// form a composite partition-key.
+	PartitionKeyIndex string
+	// Static columns are shared by all rows in a partition.
+	Static bool
 }
 
 // ColumnComparator Creates comparator for select queries
 type ColumnComparator struct {
-	Name    string
-	Value   interface{}
-	cmpType qb.Cmp
+	Name  string
+	Value interface{}
+	// CQL operator used in WHERE clause, such as: "=", " IN "
+	operator string
+	// Columns of tuple and token comparisons (Name is blank for these)
+	columns []string
+	token   bool
+	// Token is compared to a raw token-value, instead of TOKEN(values)
+	rawToken bool
 }
 
 // Comparator is a convenience function to create a new ColumnComparator
@@ -37,39 +69,93 @@ func Comparator(col string, value interface{}) ColumnComparator {
 	}
 }
 
+// TupleComparator creates a multi-column comparator, such as:
+//  (timestamp, uuid) > (?, ?)
+// The values must be in same order as columns. When used with #In,
+// each value must be a []interface{} tuple, such as:
+//  TupleComparator(cols, []interface{}{ts1, uuid1}, []interface{}{ts2, uuid2}).In()
+func TupleComparator(cols []string, values ...interface{}) ColumnComparator {
+	return ColumnComparator{
+		Value:   values,
+		columns: append([]string{}, cols...),
+	}
+}
+
+// TokenComparator creates a token comparator on partition-key columns, such as:
+//  TOKEN(tenant, year_bucket) > TOKEN(?, ?)
+// The values must be in same order as columns.
+func TokenComparator(cols []string, values ...interface{}) ColumnComparator {
+	return ColumnComparator{
+		Value:   values,
+		columns: append([]string{}, cols...),
+		token:   true,
+	}
+}
+
+// TokenValueComparator creates a comparator of partition-key token to a
+// raw token-value (such as an int64 for Murmur3Partitioner), such as:
+//  TOKEN(tenant, year_bucket) > ?
+func TokenValueComparator(cols []string, token interface{}) ColumnComparator {
+	return ColumnComparator{
+		Value:    token,
+		columns:  append([]string{}, cols...),
+		token:    true,
+		rawToken: true,
+	}
+}
+
 // Eq creates an Equality (=) operator
 func (cc ColumnComparator) Eq() ColumnComparator {
-	cc.cmpType = qb.Eq(cc.Name)
+	cc.operator = "="
 	return cc
 }
 
 // Gt creates a Greater-Than (>) operator
 func (cc ColumnComparator) Gt() ColumnComparator {
-	cc.cmpType = qb.Gt(cc.Name)
+	cc.operator = ">"
 	return cc
 }
 
 // GtOrEq creates a Greater-Than-Or-Equals-To (>=) operator
 func (cc ColumnComparator) GtOrEq() ColumnComparator {
-	cc.cmpType = qb.GtOrEq(cc.Name)
+	cc.operator = ">="
 	return cc
 }
 
 // In creates a Value-In-Array operator. The provided value must be an array.
 func (cc ColumnComparator) In() ColumnComparator {
-	cc.cmpType = qb.In(cc.Name)
+	cc.operator = " IN "
+	return cc
+}
+
+// Contains creates a CONTAINS operator for collection-columns.
+// For maps, this checks the map-values.
+func (cc ColumnComparator) Contains() ColumnComparator {
+	cc.operator = " CONTAINS "
+	return cc
+}
+
+// ContainsKey creates a CONTAINS KEY operator for map-columns.
+func (cc ColumnComparator) ContainsKey() ColumnComparator {
+	cc.operator = " CONTAINS KEY "
+	return cc
+}
+
+// Like creates a LIKE operator. This requires a SASI-index on column.
+func (cc ColumnComparator) Like() ColumnComparator {
+	cc.operator = " LIKE "
 	return cc
 }
 
 // Lt creates a Less-Than (<) operator
 func (cc ColumnComparator) Lt() ColumnComparator {
-	cc.cmpType = qb.Lt(cc.Name)
+	cc.operator = "<"
 	return cc
 }
 
 // LtOrEq creates a Less-Than-Or-Equals-To (<=) operator
 func (cc ColumnComparator) LtOrEq() ColumnComparator {
-	cc.cmpType = qb.LtOrEq(cc.Name)
+	cc.operator = "<="
 	return cc
 }
 
@@ -84,19 +170,50 @@ type SelectParams struct {
 	ResultsBind interface{}
 	// The columns to add in SELECT statement
 	SelectColumns []string
+	// Aggregates, function-calls and aliased columns to add in SELECT
+	// statement, after SelectColumns. See SelectExpression.
+	SelectExpressions []SelectExpression
+	// Clustering-columns to order the results by. These must follow
+	// the clustering-columns order, and either match or reverse the
+	// clustering-order (as in table-definition) for all columns.
+	OrderBy []ColumnOrder
+	// Adds DISTINCT to query. Only partition-key and static columns can be
+	// selected, and all partition-key columns are selected if SelectColumns
+	// is not specified.
+	Distinct bool
+	// Add PER PARTITION LIMIT parameter to the query
+	PerPartitionLimit uint
+	// Primary-key prefix (including all partition-key columns) to group
+	// the results by.
+	GroupBy []string
+	// Add ALLOW FILTERING to the query
+	AllowFiltering bool
+	// Skips validating ColumnValues restrictions against primary-key.
+	// Use this when restricting columns with secondary-indexes.
+	SkipRestrictionValidation bool
+	// Max number of concurrent queries for #SelectPartitions, defaults to 4
+	Concurrency uint
 }
 
 // Table contains functions to help interact with table,
 // which was created using the provided definition.
 type Table struct {
+	bucketing           *Bucketing
 	columns             []string
 	columnsWithDataType [][]string
-	definition          *map[string]TableColumn
-	keyspace            *Keyspace
-	name                string
+	// Key for signing paging-cursors
+	cursorKey  []byte
+	definition *map[string]TableColumn
+	keyspace   *Keyspace
+	name       string
+	// Timeout for schema-agreement after DDL operations
+	schemaAgreementTimeout time.Duration
+	serialConsistency      cql.SerialConsistency
+	versionColumn          string
 	// This facilitates mocking by allowing overwriting these
 	initIterx  func(q driver.QueryI) driver.IterxI
 	initQueryx func(q driver.QueryI, names []string) driver.QueryxI
+	options    *TableOptions
 	schema     *map[string]string
 	session    driver.SessionI
 }
@@ -115,14 +232,29 @@ func (t *Table) Schema() *map[string]string {
 }
 
 // AsyncInsert asynchronously inserts the specified data into table.
+// If table has Bucketing, the bucket-column field of dataStruct (which must
+// be a pointer) is set from its time-column field.
 func (t *Table) AsyncInsert(dataStruct interface{}) <-chan error {
 	errChan := make(chan error)
+	// Bucket is set before spawning goroutine, so dataStruct isn't
+	// modified concurrently with the caller
+	if t.bucketing != nil {
+		err := t.bucketing.setBucket(dataStruct)
+		if err != nil {
+			go func() {
+				errChan <- err
+			}()
+			return (<-chan error)(errChan)
+		}
+	}
 	go func() {
-		stmt, columns := qb.Insert(t.FullName()).
-			Columns(t.Columns()...).
+		columns := t.Columns()
+		stmt, _ := qb.Insert(t.FullName()).
+			Columns(quoteIdentifiers(columns)...).
 			ToCql()
 
 		q := t.Session().Query(stmt)
+		// Unquoted column-names are used for binding struct-fields
 		err := t.initQueryx(q, columns).
 			BindStruct(dataStruct).
 			ExecRelease()
@@ -135,28 +267,17 @@ func (t *Table) AsyncInsert(dataStruct interface{}) <-chan error {
 // containing the returned data. This slice is same as specified
 // in SelectParams.ResultsBind
 func (t *Table) Select(p SelectParams) (interface{}, error) {
-	var cmp []qb.Cmp
-	values := []interface{}{}
-	for _, v := range p.ColumnValues {
-		cmp = append(cmp, v.cmpType)
-		values = append(values, v.Value)
-	}
-
-	sb := qb.Select(t.FullName()).
-		Columns(p.SelectColumns...).
-		Where(cmp...)
-	if p.Limit != 0 {
-		sb.Limit(p.Limit)
+	stmt, values, err := t.selectStatement(p)
+	if err != nil {
+		return nil, err
 	}
-
-	stmt, _ := sb.ToCql()
 	q := t.Session().Query(stmt, values...)
 	if p.PageSize != 0 {
 		q.SetPageSize(p.PageSize)
 	}
 
 	i := t.initIterx(q)
-	err := i.Select(p.ResultsBind)
+	err = i.Select(p.ResultsBind)
 	if err != nil {
 		i.Close()
 		return nil, err
@@ -165,6 +286,11 @@ func (t *Table) Select(p SelectParams) (interface{}, error) {
 	return p.ResultsBind, err
 }
 
+// Bucketing returns the time-bucketing, as set in TableConfig.
+func (t *Table) Bucketing() *Bucketing {
+	return t.bucketing
+}
+
 // Keyspace returns the table keyspace as specified when creating new table.
 // This can only be set when #NewTable function is called.
 func (t *Table) Keyspace() *Keyspace {
@@ -177,8 +303,13 @@ func (t *Table) Name() string {
 }
 
 // FullName returns the table-name in keyspace.table format.
+// The keyspace and table names are quoted if required. See #QuoteIdentifier.
 func (t *Table) FullName() string {
-	return fmt.Sprintf("%s.%s", t.Keyspace().Name(), t.Name())
+	return fmt.Sprintf(
+		"%s.%s",
+		QuoteIdentifier(t.Keyspace().Name()),
+		QuoteIdentifier(t.Name()),
+	)
 }
 
 // Session returns the database-session used to create the table instance.
@@ -186,7 +317,7 @@ func (t *Table) Session() driver.SessionI {
 	return t.session
 }
 
-// Columns returns all table-columns.
+// Columns returns all table-columns, sorted by name.
 func (t *Table) Columns() []string {
 	if t.columns == nil {
 		var columns []string
@@ -197,6 +328,7 @@ func (t *Table) Columns() []string {
 				columns = append(columns, key)
 			}
 		}
+		sort.Strings(columns)
 		t.columns = columns
 	}
 	return t.columns
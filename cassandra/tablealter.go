@@ -0,0 +1,181 @@
+package cassandra
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Options returns the table-properties as specified when creating
+// the table, with the changes made using #AlterOptions.
+func (t *Table) Options() *TableOptions {
+	return t.options
+}
+
+// Drop drops the table from database. If ifExists is true,
+// no error is returned if the table doesn't exist.
+func (t *Table) Drop(ifExists bool) error {
+	query := "DROP TABLE"
+	if ifExists {
+		query += " IF EXISTS"
+	}
+	return t.ddlQuery(fmt.Sprintf("%s %s", query, t.FullName()))
+}
+
+// Truncate removes all data from the table.
+func (t *Table) Truncate() error {
+	return t.Session().
+		Query(fmt.Sprintf("TRUNCATE %s", t.FullName())).
+		Exec()
+}
+
+// AlterOptions changes the table-properties. Only the options
+// specified in provided TableOptions are changed.
+func (t *Table) AlterOptions(options TableOptions) error {
+	err := options.validate()
+	if err != nil {
+		return err
+	}
+	properties := options.toCql()
+	if len(properties) == 0 {
+		return errors.New("No table-options specified to alter")
+	}
+
+	err = t.ddlQuery(fmt.Sprintf(
+		"ALTER TABLE %s WITH %s", t.FullName(), strings.Join(properties, " AND "),
+	))
+	if err != nil {
+		return err
+	}
+	t.options = t.options.merge(options)
+	return nil
+}
+
+// AddColumn adds the provided column to the table. The column-name is used
+// as the key in table-definition. Primary-key columns cannot be added.
+func (t *Table) AddColumn(column TableColumn) error {
+	err := ValidateIdentifier(column.Name)
+	if err != nil {
+		return err
+	}
+	if column.PrimaryKeyIndex != "" || column.PartitionKeyIndex != "" {
+		return fmt.Errorf(
+			"Primary-key columns cannot be added to existing table. Errored Key: \"%s\"",
+			column.Name,
+		)
+	}
+	if t.definitionKey(column.Name) != "" {
+		return fmt.Errorf("Column \"%s\" already exists", column.Name)
+	}
+
+	definition := t.copyDefinition()
+	definition[column.Name] = column
+	return t.alterDefinition(definition, fmt.Sprintf(
+		"ALTER TABLE %s ADD %s %s",
+		t.FullName(),
+		QuoteIdentifier(column.Name),
+		columnDataType(column),
+	))
+}
+
+// DropColumn drops the specified column (the column-name as in
+// database) from the table. Primary-key columns cannot be dropped.
+func (t *Table) DropColumn(name string) error {
+	key := t.definitionKey(name)
+	if key == "" {
+		return fmt.Errorf("No column matching %s was found", name)
+	}
+	definition := t.copyDefinition()
+	if definition[key].PrimaryKeyIndex != "" {
+		return fmt.Errorf("Primary-key column \"%s\" cannot be dropped", name)
+	}
+
+	delete(definition, key)
+	return t.alterDefinition(definition, fmt.Sprintf(
+		"ALTER TABLE %s DROP %s", t.FullName(), QuoteIdentifier(name),
+	))
+}
+
+// RenameClusteringColumn renames the specified clustering-column (the
+// column-name as in database). Only clustering-columns can be renamed.
+func (t *Table) RenameClusteringColumn(from string, to string) error {
+	err := ValidateIdentifier(to)
+	if err != nil {
+		return err
+	}
+	key := t.definitionKey(from)
+	if key == "" {
+		return fmt.Errorf("No column matching %s was found", from)
+	}
+	if t.definitionKey(to) != "" {
+		return fmt.Errorf("Column \"%s\" already exists", to)
+	}
+
+	definition := t.copyDefinition()
+	column := definition[key]
+	if column.PrimaryKeyIndex == "" || column.PrimaryKeyIndex == "0" {
+		return fmt.Errorf(
+			"Only clustering-columns can be renamed. Errored Key: \"%s\"", from,
+		)
+	}
+
+	column.Name = to
+	definition[key] = column
+	return t.alterDefinition(definition, fmt.Sprintf(
+		"ALTER TABLE %s RENAME %s TO %s",
+		t.FullName(),
+		QuoteIdentifier(from),
+		QuoteIdentifier(to),
+	))
+}
+
+// alterDefinition executes the provided DDL query, and then replaces
+// the table-definition with provided one. The schema and cached columns
+// are regenerated from new definition.
+func (t *Table) alterDefinition(definition map[string]TableColumn, query string) error {
+	schema, err := schemaFromDefinition(&definition)
+	if err != nil {
+		return err
+	}
+	err = t.ddlQuery(query)
+	if err != nil {
+		return err
+	}
+
+	t.definition = &definition
+	t.schema = schema
+	t.columns = nil
+	t.columnsWithDataType = nil
+	return nil
+}
+
+// ddlQuery executes the provided DDL query, and waits for schema-agreement
+// if TableConfig.SchemaAgreementTimeout was set.
+func (t *Table) ddlQuery(query string) error {
+	err := t.Session().Query(query).Exec()
+	if err != nil {
+		return err
+	}
+	return awaitSchemaAgreement(t.Session(), t.schemaAgreementTimeout, t.keyspace.initIterx)
+}
+
+// copyDefinition returns a copy of table-definition. This is modified
+// when altering table, so the definition provided by user isn't changed.
+func (t *Table) copyDefinition() map[string]TableColumn {
+	definition := make(map[string]TableColumn, len(*t.Definition()))
+	for key, column := range *t.Definition() {
+		definition[key] = column
+	}
+	return definition
+}
+
+// definitionKey returns the key in table-definition for the provided
+// column-name (as in database). Returns blank string if no such column exists.
+func (t *Table) definitionKey(name string) string {
+	for key, column := range *t.Definition() {
+		if column.Name == name {
+			return key
+		}
+	}
+	return ""
+}
@@ -0,0 +1,223 @@
+package cassandra
+
+import (
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	"github.com/TerrexTech/go-commonutils/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Table", func() {
+	Context("table is altered", func() {
+		var (
+			definition *map[string]TableColumn
+			outputStr  string
+			session    *mocks.Session
+			table      *Table
+		)
+
+		BeforeEach(func() {
+			definition = &map[string]TableColumn{
+				"text1": TableColumn{
+					Name:     "textcol1",
+					DataType: "text",
+				},
+				"timestamp": TableColumn{
+					Name:            "timestamp",
+					DataType:        "timestamp",
+					PrimaryKeyIndex: "1",
+					PrimaryKeyOrder: "DESC",
+				},
+				"monthBucket": TableColumn{
+					Name:            "month_bucket",
+					DataType:        "smallint",
+					PrimaryKeyIndex: "0",
+				},
+			}
+
+			session = &mocks.Session{
+				MockQuery: func(stmt string, values ...interface{}) {
+					outputStr = utils.StandardizeSpaces(stmt)
+				},
+			}
+			keyspace, err := NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			table, err = NewTable(session, &TableConfig{
+				Keyspace: keyspace,
+				Name:     "test_table",
+			}, definition)
+			Expect(err).ToNot(HaveOccurred())
+			// Populate the cached columns
+			Expect(table.Columns()).To(HaveLen(3))
+			Expect(table.ColumnsWithDataType()).To(HaveLen(3))
+		})
+
+		It("should drop the table", func() {
+			err := table.Drop(false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outputStr).To(Equal("DROP TABLE test.test_table"))
+
+			err = table.Drop(true)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outputStr).To(Equal("DROP TABLE IF EXISTS test.test_table"))
+		})
+
+		It("should truncate the table", func() {
+			err := table.Truncate()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outputStr).To(Equal("TRUNCATE test.test_table"))
+		})
+
+		It("should alter table-options", func() {
+			ttl := 3600
+			options := TableOptions{
+				Comment:           "it's a table",
+				DefaultTimeToLive: &ttl,
+				Compaction: map[string]string{
+					"class": "TimeWindowCompactionStrategy",
+				},
+			}
+			err := table.AlterOptions(options)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outputStr).To(Equal(
+				"ALTER TABLE test.test_table WITH comment = 'it''s a table'" +
+					" AND compaction = {'class': 'TimeWindowCompactionStrategy'}" +
+					" AND default_time_to_live = 3600",
+			))
+			Expect(*table.Options()).To(Equal(options))
+
+			gcGrace := 86400
+			err = table.AlterOptions(TableOptions{
+				GCGraceSeconds: &gcGrace,
+				Extra:          map[string]string{"crc_check_chance": "0.5"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outputStr).To(Equal(
+				"ALTER TABLE test.test_table WITH crc_check_chance = 0.5" +
+					" AND gc_grace_seconds = 86400",
+			))
+			options.GCGraceSeconds = &gcGrace
+			options.Extra = map[string]string{"crc_check_chance": "0.5"}
+			Expect(*table.Options()).To(Equal(options))
+		})
+
+		It("should return error if no table-options are specified", func() {
+			err := table.AlterOptions(TableOptions{})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should add column and update table-structures", func() {
+			err := table.AddColumn(TableColumn{
+				Name:     "order",
+				DataType: "int",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outputStr).To(Equal(`ALTER TABLE test.test_table ADD "order" int`))
+
+			Expect((*table.Definition())["order"].DataType).To(Equal("int"))
+			Expect((*table.Schema())["order"]).To(Equal("int"))
+			Expect(table.Columns()).To(ContainElement("order"))
+			Expect(table.ColumnsWithDataType()).To(ContainElement([]string{"order", "int"}))
+			// Definition provided by user is not modified
+			Expect(*definition).To(HaveLen(3))
+		})
+
+		It("should return error when adding primary-key or existing columns", func() {
+			err := table.AddColumn(TableColumn{
+				Name:            "uuid",
+				DataType:        "uuid",
+				PrimaryKeyIndex: "2",
+			})
+			Expect(err).To(HaveOccurred())
+
+			err = table.AddColumn(TableColumn{
+				Name:     "textcol1",
+				DataType: "text",
+			})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should drop column and update table-structures", func() {
+			err := table.DropColumn("textcol1")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outputStr).To(Equal("ALTER TABLE test.test_table DROP textcol1"))
+
+			Expect(*table.Definition()).ToNot(HaveKey("text1"))
+			Expect(*table.Schema()).ToNot(HaveKey("textcol1"))
+			Expect(table.Columns()).ToNot(ContainElement("textcol1"))
+			Expect(table.ColumnsWithDataType()).To(HaveLen(2))
+		})
+
+		It("should return error when dropping primary-key or unknown columns", func() {
+			Expect(table.DropColumn("month_bucket")).To(HaveOccurred())
+			Expect(table.DropColumn("invalid")).To(HaveOccurred())
+		})
+
+		It("should rename clustering-column and update table-structures", func() {
+			err := table.RenameClusteringColumn("timestamp", "event_time")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outputStr).To(Equal(
+				"ALTER TABLE test.test_table RENAME timestamp TO event_time",
+			))
+
+			Expect((*table.Definition())["timestamp"].Name).To(Equal("event_time"))
+			Expect((*table.Schema())["PRIMARY KEY"]).To(Equal("(month_bucket, event_time)"))
+			Expect((*table.Schema())["WITH CLUSTERING ORDER BY"]).To(Equal("(event_time DESC)"))
+			Expect(table.Columns()).To(ContainElement("event_time"))
+			Expect(table.Columns()).ToNot(ContainElement("timestamp"))
+		})
+
+		It("should only rename clustering-columns", func() {
+			Expect(table.RenameClusteringColumn("month_bucket", "bucket")).To(HaveOccurred())
+			Expect(table.RenameClusteringColumn("textcol1", "text")).To(HaveOccurred())
+		})
+
+		It("should not update table-structures if query fails", func() {
+			session.MockQueryExecError = "some-error"
+			err := table.DropColumn("textcol1")
+			Expect(err).To(HaveOccurred())
+			Expect(*table.Definition()).To(HaveKey("text1"))
+			Expect(table.Columns()).To(ContainElement("textcol1"))
+		})
+	})
+
+	Context("table is created with options", func() {
+		It("should include the options in query", func() {
+			var outputStr string
+			session := &mocks.Session{
+				MockQuery: func(stmt string, values ...interface{}) {
+					outputStr = utils.StandardizeSpaces(stmt)
+				},
+			}
+			keyspace, err := NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			gcGrace := 0
+			_, err = NewTable(session, &TableConfig{
+				Keyspace: keyspace,
+				Name:     "test_table",
+				Options: &TableOptions{
+					GCGraceSeconds: &gcGrace,
+				},
+			}, &map[string]TableColumn{
+				"key": TableColumn{
+					Name:            "key",
+					DataType:        "text",
+					PrimaryKeyIndex: "0",
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(outputStr).To(Equal(
+				`CREATE TABLE IF NOT EXISTS test.test_table` +
+					` ("key" text, PRIMARY KEY ("key")) WITH gc_grace_seconds = 0`,
+			))
+		})
+	})
+})
@@ -0,0 +1,228 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNotApplied is returned by #RetryCAS if the lightweight-transaction
+// wasn't applied in any of the attempts.
+var ErrNotApplied = errors.New("Lightweight-transaction was not applied")
+
+// CompareAndSet updates the row identified by keyValues (a value for every
+// primary-key column, with column-names as keys) with updates, if the
+// conditions (such as Comparator("status", "pending").Eq()) match the
+// current row. The row must exist (IF EXISTS) if no conditions are provided.
+// This executes a lightweight-transaction, and returns if it was applied,
+// and the current values of condition-columns if it wasn't.
+// If table has VersionColumn, the conditions must include the expected
+// version (such as Comparator("version", 3).Eq()), and the version is
+// incremented. The version column cannot be set in updates.
+func (t *Table) CompareAndSet(
+	ctx context.Context,
+	keyValues map[string]interface{},
+	conditions []ColumnComparator,
+	updates map[string]interface{},
+) (bool, map[string]interface{}, error) {
+	ccs, err := t.primaryKeyComparators("CompareAndSet", keyValues)
+	if err != nil {
+		return false, nil, err
+	}
+	if len(updates) == 0 {
+		return false, nil, errors.New("No values specified to update")
+	}
+
+	columns := make([]string, 0, len(updates))
+	for column := range updates {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	err = t.validateNonKeyColumns("UPDATE", columns)
+	if err != nil {
+		return false, nil, err
+	}
+	err = t.validateVersionNotSet(updates)
+	if err != nil {
+		return false, nil, err
+	}
+	assignments := make([]string, len(columns))
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		assignments[i] = fmt.Sprintf("%s=?", QuoteIdentifier(column))
+		values[i] = updates[column]
+	}
+
+	where, whereValues, err := whereClause(ccs)
+	if err != nil {
+		return false, nil, err
+	}
+	if t.versionColumn != "" {
+		conditions, expected, err := t.versionCondition(conditions)
+		if err != nil {
+			return false, nil, err
+		}
+		return t.updateVersioned(
+			ctx, assignments, values, where, whereValues, conditions, expected,
+		)
+	}
+	condition, conditionValues, err := t.ifClause(conditions)
+	if err != nil {
+		return false, nil, err
+	}
+	stmt := fmt.Sprintf(
+		"UPDATE %s SET %s %s%s",
+		t.FullName(),
+		strings.Join(assignments, ","),
+		where,
+		condition,
+	)
+	values = append(values, whereValues...)
+	return t.execCAS(ctx, stmt, append(values, conditionValues...))
+}
+
+// InsertIfNotExists inserts the struct-fields (dataStruct must be a pointer
+// to struct) as a row, if a row with same primary-key doesn't exist.
+// This executes a lightweight-transaction, and returns if it was applied,
+// and the existing row if it wasn't.
+func (t *Table) InsertIfNotExists(
+	ctx context.Context,
+	dataStruct interface{},
+) (bool, map[string]interface{}, error) {
+	columnValues, err := t.structColumnValues(dataStruct, WriteParams{})
+	if err != nil {
+		return false, nil, err
+	}
+	stmt, values := t.insertStatement(columnValues)
+	return t.execCAS(ctx, stmt+"IF NOT EXISTS ", values)
+}
+
+// execCAS executes the lightweight-transaction, with serial-consistency
+// from TableConfig.
+func (t *Table) execCAS(
+	ctx context.Context,
+	stmt string,
+	values []interface{},
+) (bool, map[string]interface{}, error) {
+	current := make(map[string]interface{})
+	applied, err := t.Session().
+		Query(stmt, values...).
+		WithContext(ctx).
+		SerialConsistency(t.serialConsistency).
+		MapScanCAS(current)
+	if err != nil {
+		return false, nil, err
+	}
+	return applied, current, nil
+}
+
+// ifClause returns the IF clause for lightweight-transaction conditions.
+// Returns IF EXISTS clause if there are no conditions.
+func (t *Table) ifClause(conditions []ColumnComparator) (string, []interface{}, error) {
+	if len(conditions) == 0 {
+		return "IF EXISTS ", []interface{}{}, nil
+	}
+
+	primaryKey := t.PrimaryKeyColumns()
+	for _, cc := range conditions {
+		if cc.columns != nil {
+			return "", nil, fmt.Errorf(
+				"IF conditions must be on single columns. Errored Key: \"%s\"",
+				cc.displayName(),
+			)
+		}
+		if t.definitionKey(cc.Name) == "" {
+			return "", nil, fmt.Errorf("No column matching %s was found", cc.Name)
+		}
+		if isPrimaryKey(primaryKey, cc.Name) {
+			return "", nil, fmt.Errorf(
+				"IF conditions cannot be on primary-key columns. Errored Key: \"%s\"",
+				cc.Name,
+			)
+		}
+		switch cc.operator {
+		case "=", "<", "<=", ">", ">=", " IN ":
+		default:
+			return "", nil, fmt.Errorf(
+				"Invalid operator \"%s\" for IF condition. Errored Key: \"%s\"",
+				strings.TrimSpace(cc.operator), cc.Name,
+			)
+		}
+	}
+
+	where, values, err := whereClause(conditions)
+	if err != nil {
+		return "", nil, err
+	}
+	return "IF " + strings.TrimPrefix(where, "WHERE "), values, nil
+}
+
+// RetryParams defines parameters for #RetryCAS.
+type RetryParams struct {
+	// Max number of attempts, defaults to 5
+	MaxAttempts int
+	// Delay after first attempt, doubled after each next attempt,
+	// defaults to 10ms. A random jitter (up to the delay) is added.
+	Backoff time.Duration
+	// Max delay between attempts, defaults to 1s
+	MaxBackoff time.Duration
+}
+
+// RetryCAS retries fn until it returns applied as true, for optimistic
+// concurrency-control. Each attempt should read the current state, and
+// apply the changes using a lightweight-transaction (such as #CompareAndSet)
+// conditioned on that state.
+// Returns ErrNotApplied if no attempt was applied, or the first error
+// returned by fn.
+// Sample:
+//  err := RetryCAS(ctx, RetryParams{}, func() (bool, error) {
+//    balance := ... // read current balance
+//    applied, _, err := table.CompareAndSet(
+//      ctx,
+//      map[string]interface{}{"id": id},
+//      []ColumnComparator{Comparator("balance", balance).Eq()},
+//      map[string]interface{}{"balance": balance + amount},
+//    )
+//    return applied, err
+//  })
+func RetryCAS(ctx context.Context, p RetryParams, fn func() (bool, error)) error {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 5
+	}
+	if p.Backoff <= 0 {
+		p.Backoff = 10 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = time.Second
+	}
+
+	delay := p.Backoff
+	for attempt := 1; ; attempt++ {
+		applied, err := fn()
+		if err != nil {
+			return err
+		}
+		if applied {
+			return nil
+		}
+		if attempt == p.MaxAttempts {
+			return ErrNotApplied
+		}
+
+		timer := time.NewTimer(delay + time.Duration(rand.Int63n(int64(delay)+1)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		delay *= 2
+		if delay > p.MaxBackoff {
+			delay = p.MaxBackoff
+		}
+	}
+}
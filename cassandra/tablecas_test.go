@@ -0,0 +1,202 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	"github.com/TerrexTech/go-commonutils/utils"
+	cql "github.com/gocql/gocql"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Table", func() {
+	Context("lightweight-transactions are executed", func() {
+		type item struct {
+			ID     int    `db:"id"`
+			Status string `db:"status"`
+		}
+
+		var (
+			stmt    string
+			values  []interface{}
+			serial  cql.SerialConsistency
+			session *mocks.Session
+			table   *Table
+		)
+
+		BeforeEach(func() {
+			session = &mocks.Session{
+				MockQuery: func(s string, v ...interface{}) {
+					stmt = utils.StandardizeSpaces(s)
+					values = v
+				},
+				MockQuerySerialConsistency: func(cons cql.SerialConsistency) {
+					serial = cons
+				},
+			}
+			keyspace, err := NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			table, err = NewTable(session, &TableConfig{
+				Keyspace: keyspace,
+				Name:     "test_table",
+			}, &map[string]TableColumn{
+				"id": TableColumn{
+					Name:            "id",
+					DataType:        "int",
+					PrimaryKeyIndex: "0",
+				},
+				"status": TableColumn{
+					Name:     "status",
+					DataType: "text",
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should update the row if conditions match", func() {
+			applied, current, err := table.CompareAndSet(
+				context.Background(),
+				map[string]interface{}{"id": 1},
+				[]ColumnComparator{Comparator("status", "pending").Eq()},
+				map[string]interface{}{"status": "done"},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(applied).To(BeTrue())
+			Expect(current).To(BeEmpty())
+			Expect(stmt).To(Equal(
+				"UPDATE test.test_table SET status=? WHERE id=? IF status=?",
+			))
+			Expect(values).To(Equal([]interface{}{"done", 1, "pending"}))
+			Expect(serial).To(Equal(cql.Serial))
+		})
+
+		It("should return current values if not applied", func() {
+			session.MockQueryMapScanCAS = func(dest map[string]interface{}) (bool, error) {
+				dest["status"] = "done"
+				return false, nil
+			}
+			applied, current, err := table.CompareAndSet(
+				context.Background(),
+				map[string]interface{}{"id": 1},
+				nil,
+				map[string]interface{}{"status": "done"},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(applied).To(BeFalse())
+			Expect(current).To(Equal(map[string]interface{}{"status": "done"}))
+			Expect(stmt).To(Equal("UPDATE test.test_table SET status=? WHERE id=? IF EXISTS"))
+		})
+
+		It("should return error on invalid conditions or updates", func() {
+			invalid := [][]ColumnComparator{
+				[]ColumnComparator{Comparator("id", 1).Eq()},
+				[]ColumnComparator{Comparator("invalid", 1).Eq()},
+				[]ColumnComparator{Comparator("status", "a").Like()},
+				[]ColumnComparator{TupleComparator([]string{"status"}, "a").Eq()},
+			}
+			for _, conditions := range invalid {
+				_, _, err := table.CompareAndSet(
+					context.Background(),
+					map[string]interface{}{"id": 1},
+					conditions,
+					map[string]interface{}{"status": "done"},
+				)
+				Expect(err).To(HaveOccurred())
+			}
+
+			_, _, err := table.CompareAndSet(
+				context.Background(), map[string]interface{}{}, nil,
+				map[string]interface{}{"status": "done"},
+			)
+			Expect(err).To(HaveOccurred())
+			_, _, err = table.CompareAndSet(
+				context.Background(), map[string]interface{}{"id": 1}, nil,
+				map[string]interface{}{"id": 2},
+			)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should insert the row if it doesn't exist", func() {
+			applied, _, err := table.InsertIfNotExists(
+				context.Background(), &item{ID: 1, Status: "new"},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(applied).To(BeTrue())
+			Expect(stmt).To(Equal(
+				"INSERT INTO test.test_table (id,status) VALUES (?,?) IF NOT EXISTS",
+			))
+			Expect(values).To(Equal([]interface{}{1, "new"}))
+
+			session.MockQueryMapScanCAS = func(dest map[string]interface{}) (bool, error) {
+				return false, errors.New("timeout")
+			}
+			_, _, err = table.InsertIfNotExists(context.Background(), &item{ID: 1})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should use the configured serial-consistency", func() {
+			var err error
+			table, err = NewTable(session, &TableConfig{
+				Keyspace:          table.Keyspace(),
+				Name:              "test_table",
+				SerialConsistency: cql.LocalSerial,
+			}, table.Definition())
+			Expect(err).ToNot(HaveOccurred())
+
+			_, _, err = table.InsertIfNotExists(context.Background(), &item{ID: 1})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(serial).To(Equal(cql.LocalSerial))
+		})
+	})
+
+	Context("lightweight-transactions are retried", func() {
+		params := RetryParams{
+			MaxAttempts: 3,
+			Backoff:     time.Millisecond,
+		}
+
+		It("should retry until applied", func() {
+			attempts := 0
+			err := RetryCAS(context.Background(), params, func() (bool, error) {
+				attempts++
+				return attempts == 2, nil
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(attempts).To(Equal(2))
+		})
+
+		It("should return ErrNotApplied after max attempts", func() {
+			attempts := 0
+			err := RetryCAS(context.Background(), params, func() (bool, error) {
+				attempts++
+				return false, nil
+			})
+			Expect(err).To(Equal(ErrNotApplied))
+			Expect(attempts).To(Equal(3))
+		})
+
+		It("should stop on error or canceled context", func() {
+			attempts := 0
+			err := RetryCAS(context.Background(), params, func() (bool, error) {
+				attempts++
+				return false, errors.New("some error")
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(attempts).To(Equal(1))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err = RetryCAS(ctx, params, func() (bool, error) {
+				return false, nil
+			})
+			Expect(err).To(Equal(context.Canceled))
+		})
+	})
+})
@@ -3,7 +3,6 @@ package cassandra
 import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/scylladb/gocqlx/qb"
 )
 
 var _ = Describe("ColumnComparator", func() {
@@ -24,28 +23,99 @@ var _ = Describe("ColumnComparator", func() {
 		})
 
 		Specify("Eq on Eq operation", func() {
-			cc = cc.Eq()
-			Expect(cc.cmpType).To(Equal(qb.Eq(cc.Name)))
+			condition, _, err := cc.Eq().toCql()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition).To(Equal("test=?"))
 		})
 
 		Specify("Gt on Gt operation", func() {
-			cc = cc.Gt()
-			Expect(cc.cmpType).To(Equal(qb.Gt(cc.Name)))
+			condition, _, err := cc.Gt().toCql()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition).To(Equal("test>?"))
 		})
 
 		Specify("GtOrEq on GtOrEq operation", func() {
-			cc = cc.GtOrEq()
-			Expect(cc.cmpType).To(Equal(qb.GtOrEq(cc.Name)))
+			condition, _, err := cc.GtOrEq().toCql()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition).To(Equal("test>=?"))
 		})
 
 		Specify("Lt on Lt operation", func() {
-			cc = cc.Lt()
-			Expect(cc.cmpType).To(Equal(qb.Lt(cc.Name)))
+			condition, _, err := cc.Lt().toCql()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition).To(Equal("test<?"))
 		})
 
 		Specify("LtOrEq on LtOrEq operation", func() {
-			cc = cc.LtOrEq()
-			Expect(cc.cmpType).To(Equal(qb.LtOrEq(cc.Name)))
+			condition, _, err := cc.LtOrEq().toCql()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition).To(Equal("test<=?"))
+		})
+
+		Specify("Contains on Contains operation", func() {
+			condition, _, err := cc.Contains().toCql()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition).To(Equal("test CONTAINS ?"))
+		})
+
+		Specify("ContainsKey on ContainsKey operation", func() {
+			condition, values, err := cc.ContainsKey().toCql()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition).To(Equal("test CONTAINS KEY ?"))
+			Expect(values).To(Equal([]interface{}{1}))
+		})
+
+		Specify("Like on Like operation", func() {
+			condition, _, err := Comparator("name", "Jo%").Like().toCql()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition).To(Equal("name LIKE ?"))
+		})
+	})
+
+	Context("tuple and token comparators are created", func() {
+		It("should compare multiple columns as tuple", func() {
+			cc := TupleComparator([]string{"timestamp", "uuid"}, 1, 2).Gt()
+			condition, values, err := cc.toCql()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition).To(Equal("(timestamp,uuid)>(?,?)"))
+			Expect(values).To(Equal([]interface{}{1, 2}))
+		})
+
+		It("should use IN on multiple columns", func() {
+			cc := TupleComparator(
+				[]string{"timestamp", "uuid"},
+				[]interface{}{1, 2},
+				[]interface{}{3, 4},
+			).In()
+			condition, values, err := cc.toCql()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition).To(Equal("(timestamp,uuid) IN ((?,?),(?,?))"))
+			Expect(values).To(Equal([]interface{}{1, 2, 3, 4}))
+		})
+
+		It("should compare tokens of partition-key columns", func() {
+			cc := TokenComparator([]string{"tenant", "year_bucket"}, "t1", 2018).GtOrEq()
+			condition, values, err := cc.toCql()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(condition).To(Equal("TOKEN(tenant,year_bucket)>=TOKEN(?,?)"))
+			Expect(values).To(Equal([]interface{}{"t1", 2018}))
+		})
+
+		It("should return error on mismatched values or unsupported operators", func() {
+			_, _, err := TupleComparator([]string{"a", "b"}, 1).Lt().toCql()
+			Expect(err).To(HaveOccurred())
+
+			_, _, err = TupleComparator([]string{"a", "b"}, 1, 2).In().toCql()
+			Expect(err).To(HaveOccurred())
+
+			_, _, err = TokenComparator([]string{"a"}, []interface{}{1}).In().toCql()
+			Expect(err).To(HaveOccurred())
+
+			_, _, err = TupleComparator([]string{"a", "b"}, 1, 2).Contains().toCql()
+			Expect(err).To(HaveOccurred())
+
+			_, _, err = TupleComparator(nil).Eq().toCql()
+			Expect(err).To(HaveOccurred())
 		})
 	})
 })
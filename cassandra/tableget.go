@@ -0,0 +1,86 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	cql "github.com/gocql/gocql"
+)
+
+// ErrNotFound is returned by #Get if no row matches the provided primary-key.
+var ErrNotFound = errors.New("No row found matching the primary-key")
+
+// Get gets a single row by its complete primary-key, and loads it into dest
+// (pointer to struct). The keyValues must have a value for every primary-key
+// column, with column-names (as in database) as keys.
+// Returns ErrNotFound if the row doesn't exist.
+func (t *Table) Get(
+	ctx context.Context,
+	keyValues map[string]interface{},
+	dest interface{},
+) error {
+	ccs, err := t.primaryKeyComparators("Get", keyValues)
+	if err != nil {
+		return err
+	}
+
+	stmt, values, err := t.selectStatement(SelectParams{
+		ColumnValues: ccs,
+	})
+	if err != nil {
+		return err
+	}
+	q := t.Session().Query(stmt, values...).WithContext(ctx)
+
+	// Iterator is closed by #Get
+	err = t.initIterx(q).Get(dest)
+	if err == cql.ErrNotFound {
+		return ErrNotFound
+	}
+	return err
+}
+
+// primaryKeyComparators returns the Eq comparators for primary-key columns,
+// with values from keyValues. The keyValues must have a value for every
+// primary-key column, and no other values.
+func (t *Table) primaryKeyComparators(
+	op string,
+	keyValues map[string]interface{},
+) ([]ColumnComparator, error) {
+	primaryKey := t.PrimaryKeyColumns()
+	ccs := make([]ColumnComparator, len(primaryKey))
+	for i, column := range primaryKey {
+		value, exists := keyValues[column]
+		if !exists {
+			return nil, fmt.Errorf("%s requires a value for primary-key column %s", op, column)
+		}
+		ccs[i] = Comparator(column, value).Eq()
+	}
+	if len(keyValues) != len(primaryKey) {
+		columns := make([]string, 0, len(keyValues))
+		for column := range keyValues {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		for _, column := range columns {
+			if !isPrimaryKey(primaryKey, column) {
+				return nil, fmt.Errorf(
+					"%s only accepts primary-key columns. Errored Key: \"%s\"", op, column,
+				)
+			}
+		}
+	}
+	return ccs, nil
+}
+
+// isPrimaryKey checks if column is in primaryKey columns.
+func isPrimaryKey(primaryKey []string, column string) bool {
+	for _, key := range primaryKey {
+		if key == column {
+			return true
+		}
+	}
+	return false
+}
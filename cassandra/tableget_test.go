@@ -0,0 +1,128 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	"github.com/TerrexTech/go-commonutils/utils"
+	cql "github.com/gocql/gocql"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Table", func() {
+	Context("single row is fetched by primary-key", func() {
+		type item struct {
+			Tenant string `db:"tenant"`
+			ID     int    `db:"id"`
+			Data   string `db:"data"`
+		}
+
+		var (
+			stmt     string
+			values   []interface{}
+			queryCtx context.Context
+			getError error
+			table    *Table
+		)
+
+		BeforeEach(func() {
+			session := &mocks.Session{
+				MockQuery: func(s string, v ...interface{}) {
+					stmt = utils.StandardizeSpaces(s)
+					values = v
+				},
+			}
+			keyspace, err := NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			table, err = NewTable(session, &TableConfig{
+				Keyspace: keyspace,
+				Name:     "test_table",
+			}, &map[string]TableColumn{
+				"tenant": TableColumn{
+					Name:            "tenant",
+					DataType:        "text",
+					PrimaryKeyIndex: "0",
+				},
+				"id": TableColumn{
+					Name:            "id",
+					DataType:        "int",
+					PrimaryKeyIndex: "1",
+				},
+				"data": TableColumn{
+					Name:     "data",
+					DataType: "text",
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			getError = nil
+			table.initIterx = func(q driver.QueryI) driver.IterxI {
+				queryCtx = q.(*mocks.Query).Context()
+				return &mocks.Iterx{
+					CqlQuery: q,
+					MockGet: func(dest interface{}) error {
+						if getError != nil {
+							return getError
+						}
+						*dest.(*item) = item{"t1", 1, "d"}
+						return nil
+					},
+				}
+			}
+		})
+
+		It("should get the row matching primary-key", func() {
+			type ctxKey string
+			ctx := context.WithValue(context.Background(), ctxKey("key"), "value")
+			result := item{}
+			err := table.Get(ctx, map[string]interface{}{
+				"tenant": "t1",
+				"id":     1,
+			}, &result)
+
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(item{"t1", 1, "d"}))
+			Expect(stmt).To(Equal("SELECT * FROM test.test_table WHERE tenant=? AND id=?"))
+			Expect(values).To(Equal([]interface{}{"t1", 1}))
+			Expect(queryCtx).To(Equal(ctx))
+		})
+
+		It("should return ErrNotFound if row doesn't exist", func() {
+			getError = cql.ErrNotFound
+			err := table.Get(context.Background(), map[string]interface{}{
+				"tenant": "t1",
+				"id":     1,
+			}, &item{})
+			Expect(err).To(Equal(ErrNotFound))
+
+			getError = errors.New("some-error")
+			err = table.Get(context.Background(), map[string]interface{}{
+				"tenant": "t1",
+				"id":     1,
+			}, &item{})
+			Expect(err).To(Equal(getError))
+		})
+
+		It("should return error if primary-key is incomplete or has other columns", func() {
+			err := table.Get(context.Background(), map[string]interface{}{
+				"tenant": "t1",
+			}, &item{})
+			Expect(err).To(HaveOccurred())
+
+			err = table.Get(context.Background(), map[string]interface{}{
+				"tenant": "t1",
+				"id":     1,
+				"data":   "d",
+			}, &item{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("data"))
+		})
+	})
+})
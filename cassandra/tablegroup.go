@@ -0,0 +1,318 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	cqlx "github.com/scylladb/gocqlx"
+	"github.com/scylladb/gocqlx/reflectx"
+)
+
+// TableGroup writes an entity to several denormalized tables (such as
+// query-tables for different access-patterns) atomically, using logged
+// batches. The first registered table is the primary table, which the
+// other tables are compared with by #Check.
+type TableGroup struct {
+	members []groupMember
+}
+
+// groupMember is a table in TableGroup, with its field-mapping.
+type groupMember struct {
+	table *Table
+	// Entity-fields for table-columns, with column-names as keys
+	fields map[string]string
+}
+
+// Inconsistency is a difference between the primary table of TableGroup
+// and another table, as found by #Check.
+type Inconsistency struct {
+	Table *Table
+	// Entity-field with different values, empty if the row is missing
+	Field string
+	// Value in primary table, nil if row is missing from primary table
+	Expected interface{}
+	// Value in Table, nil if row is missing from Table
+	Actual interface{}
+	// The row exists in only one of primary table and Table
+	MissingRow bool
+}
+
+// NewTableGroup creates an empty TableGroup. See #Register.
+func NewTableGroup() *TableGroup {
+	return &TableGroup{
+		members: []groupMember{},
+	}
+}
+
+// Register adds the table to group. The fields map the table-columns (keys)
+// to entity-fields (values), where the entity-fields are named as in "db"
+// struct-tags (or snake-cased field-names). The columns not in fields map
+// to entity-fields with same name. If table has Bucketing, the bucket-column
+// is computed from time-column, and doesn't need an entity-field.
+func (g *TableGroup) Register(table *Table, fields map[string]string) error {
+	for _, member := range g.members {
+		if member.table.FullName() == table.FullName() {
+			return fmt.Errorf("Table %s is already registered", table.FullName())
+		}
+	}
+	columns := make([]string, 0, len(fields))
+	for column := range fields {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	for _, column := range columns {
+		if table.definitionKey(column) == "" {
+			return fmt.Errorf(
+				"No column matching %s was found in table %s", column, table.FullName(),
+			)
+		}
+	}
+
+	mapping := make(map[string]string)
+	for _, column := range table.Columns() {
+		mapping[column] = column
+		if field, exists := fields[column]; exists {
+			mapping[column] = field
+		}
+	}
+	g.members = append(g.members, groupMember{
+		table:  table,
+		fields: mapping,
+	})
+	return nil
+}
+
+// Tables returns the registered tables, in registration order.
+func (g *TableGroup) Tables() []*Table {
+	tables := make([]*Table, len(g.members))
+	for i, member := range g.members {
+		tables[i] = member.table
+	}
+	return tables
+}
+
+// Write inserts the entity (a struct or pointer to struct) into all
+// tables in a logged batch.
+func (g *TableGroup) Write(ctx context.Context, entity interface{}) error {
+	fieldValues, err := g.entityValues(entity)
+	if err != nil {
+		return err
+	}
+
+	stmts := make([]string, len(g.members))
+	values := []interface{}{}
+	for i, member := range g.members {
+		columnValues, err := member.columnValues(fieldValues)
+		if err != nil {
+			return err
+		}
+		var tableValues []interface{}
+		stmts[i], tableValues = member.table.insertStatement(columnValues)
+		values = append(values, tableValues...)
+	}
+	return g.execBatch(ctx, stmts, values)
+}
+
+// Delete deletes the rows of entity (a struct or pointer to struct) from
+// all tables in a logged batch. The rows are identified by the entity-fields
+// mapped to primary-key columns.
+func (g *TableGroup) Delete(ctx context.Context, entity interface{}) error {
+	fieldValues, err := g.entityValues(entity)
+	if err != nil {
+		return err
+	}
+
+	stmts := make([]string, len(g.members))
+	values := []interface{}{}
+	for i, member := range g.members {
+		ccs, err := member.keyComparators(fieldValues)
+		if err != nil {
+			return err
+		}
+		where, whereValues, err := whereClause(ccs)
+		if err != nil {
+			return err
+		}
+		stmts[i] = fmt.Sprintf("DELETE FROM %s %s", member.table.FullName(), where)
+		values = append(values, whereValues...)
+	}
+	return g.execBatch(ctx, stmts, values)
+}
+
+// Check reads the rows of entity (a struct or pointer to struct) from all
+// tables, and compares the rows in other tables with the row in primary
+// table. Returns the found inconsistencies, which is empty if the rows
+// are consistent.
+func (g *TableGroup) Check(
+	ctx context.Context,
+	entity interface{},
+) ([]Inconsistency, error) {
+	if len(g.members) == 0 {
+		return nil, errors.New("No tables registered in TableGroup")
+	}
+	fieldValues, err := g.entityValues(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]map[string]interface{}, len(g.members))
+	for i, member := range g.members {
+		rows[i], err = member.readRow(ctx, fieldValues)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	inconsistencies := []Inconsistency{}
+	primary := g.members[0]
+	for i, member := range g.members[1:] {
+		row := rows[i+1]
+		if (rows[0] == nil) != (row == nil) {
+			inconsistencies = append(inconsistencies, Inconsistency{
+				Table:      member.table,
+				MissingRow: true,
+			})
+			continue
+		}
+		if row == nil {
+			continue
+		}
+
+		for _, column := range member.table.Columns() {
+			field := member.fields[column]
+			primaryColumn := primary.column(field)
+			if primaryColumn == "" {
+				continue
+			}
+			expected, actual := rows[0][primaryColumn], row[column]
+			if !reflect.DeepEqual(expected, actual) {
+				inconsistencies = append(inconsistencies, Inconsistency{
+					Table:    member.table,
+					Field:    field,
+					Expected: expected,
+					Actual:   actual,
+				})
+			}
+		}
+	}
+	return inconsistencies, nil
+}
+
+// entityValues returns the entity's field-values, with field-names
+// (as bound to columns) as keys.
+func (g *TableGroup) entityValues(entity interface{}) (map[string]interface{}, error) {
+	v := reflect.Indirect(reflect.ValueOf(entity))
+	if v.Kind() != reflect.Struct {
+		return nil, errors.New("Entity must be a struct or pointer to struct")
+	}
+
+	fieldValues := make(map[string]interface{})
+	for name, fi := range cqlx.DefaultMapper.TypeMap(v.Type()).Names {
+		fieldValues[name] = reflectx.FieldByIndexesReadOnly(v, fi.Index).Interface()
+	}
+	return fieldValues, nil
+}
+
+// execBatch executes the statements in a logged batch.
+func (g *TableGroup) execBatch(
+	ctx context.Context,
+	stmts []string,
+	values []interface{},
+) error {
+	if len(stmts) == 0 {
+		return errors.New("No tables registered in TableGroup")
+	}
+	stmt := fmt.Sprintf("BEGIN BATCH %s; APPLY BATCH ", strings.Join(stmts, "; "))
+	return g.members[0].table.Session().
+		Query(stmt, values...).
+		WithContext(ctx).
+		Exec()
+}
+
+// column returns the table-column mapped to entity-field,
+// empty if the field isn't mapped.
+func (m groupMember) column(field string) string {
+	for _, column := range m.table.Columns() {
+		if m.fields[column] == field {
+			return column
+		}
+	}
+	return ""
+}
+
+// columnValues returns the values for table-columns from entity-fields,
+// with column-names as keys.
+func (m groupMember) columnValues(
+	fieldValues map[string]interface{},
+) (map[string]interface{}, error) {
+	b := m.table.bucketing
+	columnValues := make(map[string]interface{})
+	for _, column := range m.table.Columns() {
+		if b != nil && column == b.BucketColumn {
+			continue
+		}
+		value, exists := fieldValues[m.fields[column]]
+		if !exists {
+			return nil, fmt.Errorf(
+				"No entity-field %s found for column %s of table %s",
+				m.fields[column], column, m.table.FullName(),
+			)
+		}
+		columnValues[column] = value
+	}
+
+	if b != nil {
+		ts, isTime := columnValues[b.TimeColumn].(time.Time)
+		if !isTime {
+			return nil, fmt.Errorf(
+				"Bucketing requires a time.Time field for column %s of table %s",
+				b.TimeColumn, m.table.FullName(),
+			)
+		}
+		columnValues[b.BucketColumn] = b.Bucket(ts)
+	}
+	return columnValues, nil
+}
+
+// keyComparators returns the Eq comparators for primary-key columns,
+// with values from entity-fields.
+func (m groupMember) keyComparators(
+	fieldValues map[string]interface{},
+) ([]ColumnComparator, error) {
+	columnValues, err := m.columnValues(fieldValues)
+	if err != nil {
+		return nil, err
+	}
+	primaryKey := m.table.PrimaryKeyColumns()
+	ccs := make([]ColumnComparator, len(primaryKey))
+	for i, column := range primaryKey {
+		ccs[i] = Comparator(column, columnValues[column]).Eq()
+	}
+	return ccs, nil
+}
+
+// readRow reads the entity's row from table, nil if it doesn't exist.
+func (m groupMember) readRow(
+	ctx context.Context,
+	fieldValues map[string]interface{},
+) (map[string]interface{}, error) {
+	ccs, err := m.keyComparators(fieldValues)
+	if err != nil {
+		return nil, err
+	}
+
+	var row map[string]interface{}
+	err = m.table.Iterate(ctx, SelectParams{
+		ColumnValues: ccs,
+		Limit:        1,
+	}, func(r interface{}) error {
+		row = r.(map[string]interface{})
+		return nil
+	})
+	return row, err
+}
@@ -0,0 +1,177 @@
+package cassandra
+
+import (
+	"context"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	"github.com/TerrexTech/go-commonutils/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TableGroup", func() {
+	type user struct {
+		ID    int    `db:"id"`
+		Email string `db:"email"`
+		Name  string `db:"name"`
+	}
+
+	var (
+		stmt    string
+		values  []interface{}
+		session *mocks.Session
+		users   *Table
+		byEmail *Table
+		group   *TableGroup
+	)
+
+	BeforeEach(func() {
+		session = &mocks.Session{
+			MockQuery: func(s string, v ...interface{}) {
+				stmt = utils.StandardizeSpaces(s)
+				values = v
+			},
+		}
+		keyspace, err := NewKeyspace(session, KeyspaceConfig{
+			Name:        "test",
+			Replication: SimpleStrategy{ReplicationFactor: 1},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		users, err = NewTable(session, &TableConfig{
+			Keyspace: keyspace,
+			Name:     "users",
+		}, &map[string]TableColumn{
+			"id": TableColumn{
+				Name:            "id",
+				DataType:        "int",
+				PrimaryKeyIndex: "0",
+			},
+			"email": TableColumn{
+				Name:     "email",
+				DataType: "text",
+			},
+			"name": TableColumn{
+				Name:     "name",
+				DataType: "text",
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		byEmail, err = NewTable(session, &TableConfig{
+			Keyspace: keyspace,
+			Name:     "users_by_email",
+		}, &map[string]TableColumn{
+			"userEmail": TableColumn{
+				Name:            "user_email",
+				DataType:        "text",
+				PrimaryKeyIndex: "0",
+			},
+			"id": TableColumn{
+				Name:     "id",
+				DataType: "int",
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		group = NewTableGroup()
+		Expect(group.Register(users, nil)).To(Succeed())
+		Expect(group.Register(byEmail, map[string]string{
+			"user_email": "email",
+		})).To(Succeed())
+	})
+
+	It("should validate the registered tables", func() {
+		Expect(group.Tables()).To(Equal([]*Table{users, byEmail}))
+		Expect(group.Register(users, nil)).To(HaveOccurred())
+
+		g := NewTableGroup()
+		err := g.Register(byEmail, map[string]string{"invalid": "email"})
+		Expect(err).To(HaveOccurred())
+		Expect(g.Write(context.Background(), user{})).To(HaveOccurred())
+	})
+
+	It("should write entity to all tables in a logged batch", func() {
+		err := group.Write(context.Background(), &user{
+			ID:    1,
+			Email: "a@b.c",
+			Name:  "A",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stmt).To(Equal(
+			"BEGIN BATCH " +
+				"INSERT INTO test.users (email,id,name) VALUES (?,?,?) ; " +
+				"INSERT INTO test.users_by_email (id,user_email) VALUES (?,?) ; " +
+				"APPLY BATCH",
+		))
+		Expect(values).To(Equal([]interface{}{"a@b.c", 1, "A", 1, "a@b.c"}))
+
+		type partial struct {
+			ID int `db:"id"`
+		}
+		Expect(group.Write(context.Background(), partial{})).To(HaveOccurred())
+		Expect(group.Write(context.Background(), 1)).To(HaveOccurred())
+	})
+
+	It("should delete entity from all tables in a logged batch", func() {
+		err := group.Delete(context.Background(), user{ID: 1, Email: "a@b.c"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stmt).To(Equal(
+			"BEGIN BATCH " +
+				"DELETE FROM test.users WHERE id=? ; " +
+				"DELETE FROM test.users_by_email WHERE user_email=? ; " +
+				"APPLY BATCH",
+		))
+		Expect(values).To(Equal([]interface{}{1, "a@b.c"}))
+	})
+
+	It("should report inconsistencies between tables", func() {
+		setRow := func(t *Table, row map[string]interface{}) {
+			t.initIterx = func(q driver.QueryI) driver.IterxI {
+				scanned := false
+				return &mocks.Iterx{
+					MockMapScan: func(m map[string]interface{}) bool {
+						if scanned || row == nil {
+							return false
+						}
+						for k, v := range row {
+							m[k] = v
+						}
+						scanned = true
+						return true
+					},
+				}
+			}
+		}
+		entity := user{ID: 1, Email: "a@b.c"}
+
+		setRow(users, map[string]interface{}{"id": 1, "email": "a@b.c", "name": "A"})
+		setRow(byEmail, map[string]interface{}{"id": 1, "user_email": "a@b.c"})
+		inconsistencies, err := group.Check(context.Background(), entity)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inconsistencies).To(BeEmpty())
+
+		setRow(byEmail, map[string]interface{}{"id": 2, "user_email": "a@b.c"})
+		inconsistencies, err = group.Check(context.Background(), entity)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inconsistencies).To(Equal([]Inconsistency{
+			Inconsistency{
+				Table:    byEmail,
+				Field:    "id",
+				Expected: 1,
+				Actual:   2,
+			},
+		}))
+
+		setRow(byEmail, nil)
+		inconsistencies, err = group.Check(context.Background(), entity)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(inconsistencies).To(Equal([]Inconsistency{
+			Inconsistency{
+				Table:      byEmail,
+				MissingRow: true,
+			},
+		}))
+	})
+})
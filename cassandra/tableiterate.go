@@ -0,0 +1,123 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+)
+
+// Iterate gets data from table row-by-row, calling fn for each row, without
+// loading the complete results in memory. The rows are fetched from database
+// in pages of SelectParams.PageSize (driver-default if not set).
+// SelectParams.ResultsBind defines the row-type:
+//  nil: rows are map[string]interface{}, with column-names as keys
+//  pointer to struct, or to slice of structs: rows are pointers to new struct
+// The iteration stops if fn returns an error or the context is canceled,
+// and that error is returned.
+func (t *Table) Iterate(
+	ctx context.Context,
+	p SelectParams,
+	fn func(row interface{}) error,
+) error {
+	newRow, err := rowConstructor(p.ResultsBind)
+	if err != nil {
+		return err
+	}
+	stmt, values, err := t.selectStatement(p)
+	if err != nil {
+		return err
+	}
+	q := t.Session().Query(stmt, values...).WithContext(ctx)
+	if p.PageSize != 0 {
+		q.SetPageSize(p.PageSize)
+	}
+
+	i := t.initIterx(q)
+	for {
+		err = ctx.Err()
+		if err != nil {
+			i.Close()
+			return err
+		}
+		row := newRow()
+		if !scanRow(i, row) {
+			break
+		}
+		err = fn(row)
+		if err != nil {
+			i.Close()
+			return err
+		}
+	}
+	return i.Close()
+}
+
+// Stream gets data from table row-by-row, same as #Iterate, but sends the
+// rows on returned channel. The rows-channel is closed once the iteration
+// ends, after which the error-channel receives the iteration-error (nil if
+// none) and is closed. Cancel the context to stop the iteration early;
+// the rows-channel must be drained or context canceled, else the
+// iteration blocks.
+func (t *Table) Stream(
+	ctx context.Context,
+	p SelectParams,
+) (<-chan interface{}, <-chan error) {
+	rowChan := make(chan interface{})
+	errChan := make(chan error, 1)
+
+	go func() {
+		err := t.Iterate(ctx, p, func(row interface{}) error {
+			select {
+			case rowChan <- row:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		close(rowChan)
+		errChan <- err
+		close(errChan)
+	}()
+	return (<-chan interface{})(rowChan), (<-chan error)(errChan)
+}
+
+// rowConstructor returns a function which creates new rows of type
+// defined by ResultsBind. See #Iterate.
+func rowConstructor(resultsBind interface{}) (func() interface{}, error) {
+	if resultsBind == nil {
+		return func() interface{} {
+			return map[string]interface{}{}
+		}, nil
+	}
+
+	rowType := reflect.TypeOf(resultsBind)
+	isPtr := rowType.Kind() == reflect.Ptr
+	if isPtr {
+		rowType = rowType.Elem()
+		if rowType.Kind() == reflect.Slice {
+			rowType = rowType.Elem()
+		}
+		if rowType.Kind() == reflect.Ptr {
+			rowType = rowType.Elem()
+		}
+	}
+	if !isPtr || rowType.Kind() != reflect.Struct {
+		return nil, errors.New(
+			"ResultsBind must be nil, or a pointer to struct or to slice of structs",
+		)
+	}
+	return func() interface{} {
+		return reflect.New(rowType).Interface()
+	}, nil
+}
+
+// scanRow scans the next row into map or struct-pointer.
+// Returns false if there are no more rows.
+func scanRow(i driver.IterxI, row interface{}) bool {
+	if m, isMap := row.(map[string]interface{}); isMap {
+		return i.MapScan(m)
+	}
+	return i.StructScan(row)
+}
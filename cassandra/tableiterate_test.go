@@ -0,0 +1,182 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Table", func() {
+	Context("data is iterated row-by-row", func() {
+		type item struct {
+			ID   int    `db:"id"`
+			Data string `db:"data"`
+		}
+
+		var (
+			table    *Table
+			rows     []item
+			scanned  int
+			closed   int
+			pageSize uint
+			queryCtx context.Context
+		)
+
+		BeforeEach(func() {
+			session := &mocks.Session{}
+			keyspace, err := NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			table, err = NewTable(session, &TableConfig{
+				Keyspace: keyspace,
+				Name:     "test_table",
+			}, &map[string]TableColumn{
+				"id": TableColumn{
+					Name:            "id",
+					DataType:        "int",
+					PrimaryKeyIndex: "0",
+				},
+				"data": TableColumn{
+					Name:     "data",
+					DataType: "text",
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			rows = []item{{1, "a"}, {2, "b"}, {3, "c"}}
+			scanned = 0
+			closed = 0
+			pageSize = 0
+			table.initIterx = func(q driver.QueryI) driver.IterxI {
+				pageSize = q.GetPageSize()
+				queryCtx = q.(*mocks.Query).Context()
+				return &mocks.Iterx{
+					CqlQuery: q,
+					MockClose: func() error {
+						closed++
+						return nil
+					},
+					MockStructScan: func(dest interface{}) bool {
+						if scanned == len(rows) {
+							return false
+						}
+						*dest.(*item) = rows[scanned]
+						scanned++
+						return true
+					},
+					MockMapScan: func(m map[string]interface{}) bool {
+						if scanned == len(rows) {
+							return false
+						}
+						m["id"] = rows[scanned].ID
+						m["data"] = rows[scanned].Data
+						scanned++
+						return true
+					},
+				}
+			}
+		})
+
+		It("should scan rows into structs", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			results := []item{}
+			err := table.Iterate(ctx, SelectParams{
+				ResultsBind: &[]item{},
+				PageSize:    2,
+			}, func(row interface{}) error {
+				results = append(results, *row.(*item))
+				return nil
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(Equal(rows))
+			Expect(pageSize).To(Equal(uint(2)))
+			Expect(queryCtx).To(Equal(ctx))
+			Expect(closed).To(Equal(1))
+		})
+
+		It("should scan rows into maps if ResultsBind is not set", func() {
+			results := []map[string]interface{}{}
+			err := table.Iterate(
+				context.Background(),
+				SelectParams{},
+				func(row interface{}) error {
+					results = append(results, row.(map[string]interface{}))
+					return nil
+				},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(3))
+			Expect(results[2]).To(Equal(map[string]interface{}{"id": 3, "data": "c"}))
+		})
+
+		It("should stop iterating and close iterator if callback returns error", func() {
+			err := table.Iterate(context.Background(), SelectParams{
+				ResultsBind: &item{},
+			}, func(row interface{}) error {
+				return errors.New("some-error")
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(scanned).To(Equal(1))
+			Expect(closed).To(Equal(1))
+		})
+
+		It("should stop iterating if context is canceled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			err := table.Iterate(ctx, SelectParams{
+				ResultsBind: &item{},
+			}, func(row interface{}) error {
+				cancel()
+				return nil
+			})
+			Expect(err).To(Equal(context.Canceled))
+			Expect(scanned).To(Equal(1))
+			Expect(closed).To(Equal(1))
+		})
+
+		It("should return error on invalid ResultsBind", func() {
+			for _, bind := range []interface{}{item{}, &[]int{}, []item{}} {
+				err := table.Iterate(context.Background(), SelectParams{
+					ResultsBind: bind,
+				}, func(row interface{}) error {
+					return nil
+				})
+				Expect(err).To(HaveOccurred())
+			}
+		})
+
+		It("should stream rows on channel", func() {
+			rowChan, errChan := table.Stream(context.Background(), SelectParams{
+				ResultsBind: &[]item{},
+			})
+			results := []item{}
+			for row := range rowChan {
+				results = append(results, *row.(*item))
+			}
+			Expect(<-errChan).ToNot(HaveOccurred())
+			Expect(results).To(Equal(rows))
+			Expect(closed).To(Equal(1))
+		})
+
+		It("should stop streaming if context is canceled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			rowChan, errChan := table.Stream(ctx, SelectParams{
+				ResultsBind: &[]item{},
+			})
+			<-rowChan
+			cancel()
+
+			Expect(<-errChan).To(Equal(context.Canceled))
+			Expect(closed).To(Equal(1))
+			_, isOpen := <-rowChan
+			Expect(isOpen).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,151 @@
+package cassandra
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TableOptions defines the table-properties used when creating
+// or altering a table. Blank (or nil) options are not included in
+// queries, and hence the database-defaults (or existing values) are used.
+type TableOptions struct {
+	BloomFilterFPChance *float64
+	// Caching options, such as: {"keys": "ALL", "rows_per_partition": "NONE"}
+	Caching map[string]string
+	Comment string
+	// Compaction options, such as: {"class": "TimeWindowCompactionStrategy"}
+	Compaction map[string]string
+	// Compression options, such as: {"class": "LZ4Compressor"}
+	Compression map[string]string
+	// Default time-to-live for rows, in seconds
+	DefaultTimeToLive *int
+	GCGraceSeconds    *int
+	SpeculativeRetry  string
+	// Any other table-properties, with values as CQL literals.
+	// Example: {"memtable_flush_period_in_ms": "3600000"}
+	Extra map[string]string
+}
+
+// validate checks that the Extra property-names are valid identifiers.
+func (o *TableOptions) validate() error {
+	if o == nil {
+		return nil
+	}
+	for key := range o.Extra {
+		err := ValidateIdentifier(key)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// merge returns the options with changes applied, where the blank (or nil)
+// changes keep the existing options. The map-options (such as Compaction)
+// are replaced as a whole, same as in ALTER TABLE, except Extra which
+// is merged by property-name.
+func (o *TableOptions) merge(changes TableOptions) *TableOptions {
+	if o == nil {
+		return &changes
+	}
+	merged := *o
+	if changes.BloomFilterFPChance != nil {
+		merged.BloomFilterFPChance = changes.BloomFilterFPChance
+	}
+	if len(changes.Caching) > 0 {
+		merged.Caching = changes.Caching
+	}
+	if changes.Comment != "" {
+		merged.Comment = changes.Comment
+	}
+	if len(changes.Compaction) > 0 {
+		merged.Compaction = changes.Compaction
+	}
+	if len(changes.Compression) > 0 {
+		merged.Compression = changes.Compression
+	}
+	if changes.DefaultTimeToLive != nil {
+		merged.DefaultTimeToLive = changes.DefaultTimeToLive
+	}
+	if changes.GCGraceSeconds != nil {
+		merged.GCGraceSeconds = changes.GCGraceSeconds
+	}
+	if changes.SpeculativeRetry != "" {
+		merged.SpeculativeRetry = changes.SpeculativeRetry
+	}
+	if len(changes.Extra) > 0 {
+		merged.Extra = make(map[string]string)
+		for key, value := range o.Extra {
+			merged.Extra[key] = value
+		}
+		for key, value := range changes.Extra {
+			merged.Extra[key] = value
+		}
+	}
+	return &merged
+}
+
+// toCql returns the table-options as CQL property-assignments
+// (such as "comment = 'x'"), sorted by property-name.
+func (o *TableOptions) toCql() []string {
+	if o == nil {
+		return nil
+	}
+
+	options := []string{}
+	if o.BloomFilterFPChance != nil {
+		options = append(options, fmt.Sprintf(
+			"bloom_filter_fp_chance = %s",
+			strconv.FormatFloat(*o.BloomFilterFPChance, 'f', -1, 64),
+		))
+	}
+	if len(o.Caching) > 0 {
+		options = append(options, "caching = "+mapLiteral(o.Caching))
+	}
+	if o.Comment != "" {
+		options = append(options, "comment = "+quoteString(o.Comment))
+	}
+	if len(o.Compaction) > 0 {
+		options = append(options, "compaction = "+mapLiteral(o.Compaction))
+	}
+	if len(o.Compression) > 0 {
+		options = append(options, "compression = "+mapLiteral(o.Compression))
+	}
+	if o.DefaultTimeToLive != nil {
+		options = append(options, fmt.Sprintf(
+			"default_time_to_live = %d", *o.DefaultTimeToLive,
+		))
+	}
+	if o.GCGraceSeconds != nil {
+		options = append(options, fmt.Sprintf(
+			"gc_grace_seconds = %d", *o.GCGraceSeconds,
+		))
+	}
+	if o.SpeculativeRetry != "" {
+		options = append(options, "speculative_retry = "+quoteString(o.SpeculativeRetry))
+	}
+	for key, value := range o.Extra {
+		options = append(options, fmt.Sprintf("%s = %s", key, value))
+	}
+
+	sort.Strings(options)
+	return options
+}
+
+// mapLiteral returns the provided map as CQL map-literal,
+// such as: {'class': 'LZ4Compressor'}
+func mapLiteral(m map[string]string) string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	entries := make([]string, len(keys))
+	for i, key := range keys {
+		entries[i] = fmt.Sprintf("%s: %s", quoteString(key), quoteString(m[key]))
+	}
+	return fmt.Sprintf("{%s}", strings.Join(entries, ", "))
+}
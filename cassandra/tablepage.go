@@ -0,0 +1,102 @@
+package cassandra
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidCursor is returned by #SelectPage if the provided cursor cannot
+// be decoded, or its signature doesn't match (when TableConfig.CursorKey is set).
+var ErrInvalidCursor = errors.New("Invalid paging-cursor")
+
+// SelectPage gets a single page (of SelectParams.PageSize rows) from table.
+// The cursor is blank for first page, and for next pages, is the cursor
+// returned with previous page. The returned cursor is blank if there are
+// no more pages. The results are loaded into SelectParams.ResultsBind.
+// The cursors are opaque base64-strings, and are HMAC-signed (along with
+// the query) if TableConfig.CursorKey is set. A cursor must only be used
+// with the query it was returned for.
+func (t *Table) SelectPage(p SelectParams, cursor string) (interface{}, string, error) {
+	if p.PageSize == 0 {
+		return nil, "", errors.New("PageSize is required for SelectPage")
+	}
+	stmt, values, err := t.selectStatement(p)
+	if err != nil {
+		return nil, "", err
+	}
+	// The cursor is only valid for same statement and bind-values
+	query := fmt.Sprintf("%s%v", stmt, values)
+	pageState, err := t.decodeCursor(cursor, query)
+	if err != nil {
+		return nil, "", err
+	}
+
+	q := t.Session().Query(stmt, values...).
+		SetPageSize(p.PageSize).
+		// This also limits the results to single page
+		SetPageState(pageState)
+
+	i := t.initIterx(q)
+	err = i.Select(p.ResultsBind)
+	if err != nil {
+		i.Close()
+		return nil, "", err
+	}
+	nextPageState := i.PageState()
+	err = i.Close()
+	if err != nil {
+		return nil, "", err
+	}
+	return p.ResultsBind, t.encodeCursor(nextPageState, query), nil
+}
+
+// encodeCursor encodes the paging-state as base64-string, with the
+// signature appended as "state.signature" if cursor-key is set.
+func (t *Table) encodeCursor(pageState []byte, query string) string {
+	if len(pageState) == 0 {
+		return ""
+	}
+	cursor := base64.RawURLEncoding.EncodeToString(pageState)
+	if t.cursorKey == nil {
+		return cursor
+	}
+	signature := t.cursorSignature(pageState, query)
+	return cursor + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// decodeCursor returns the paging-state from cursor, after verifying
+// its signature if cursor-key is set.
+func (t *Table) decodeCursor(cursor string, query string) ([]byte, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	parts := strings.Split(cursor, ".")
+	if (t.cursorKey == nil && len(parts) != 1) || (t.cursorKey != nil && len(parts) != 2) {
+		return nil, ErrInvalidCursor
+	}
+
+	pageState, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || len(pageState) == 0 {
+		return nil, ErrInvalidCursor
+	}
+	if t.cursorKey != nil {
+		signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+		if err != nil || !hmac.Equal(signature, t.cursorSignature(pageState, query)) {
+			return nil, ErrInvalidCursor
+		}
+	}
+	return pageState, nil
+}
+
+// cursorSignature returns the HMAC-SHA256 of query and paging-state.
+func (t *Table) cursorSignature(pageState []byte, query string) []byte {
+	mac := hmac.New(sha256.New, t.cursorKey)
+	mac.Write([]byte(query))
+	mac.Write([]byte{0})
+	mac.Write(pageState)
+	return mac.Sum(nil)
+}
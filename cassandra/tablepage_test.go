@@ -0,0 +1,153 @@
+package cassandra
+
+import (
+	"errors"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Table", func() {
+	Context("data is selected in pages", func() {
+		var (
+			session       *mocks.Session
+			keyspace      *Keyspace
+			table         *Table
+			query         driver.QueryI
+			nextPageState []byte
+		)
+
+		newTable := func(cursorKey []byte) *Table {
+			t, err := NewTable(session, &TableConfig{
+				Keyspace:  keyspace,
+				Name:      "test_table",
+				CursorKey: cursorKey,
+			}, &map[string]TableColumn{
+				"id": TableColumn{
+					Name:            "id",
+					DataType:        "uuid",
+					PrimaryKeyIndex: "0",
+				},
+				"data": TableColumn{
+					Name:     "data",
+					DataType: "text",
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			t.initIterx = func(q driver.QueryI) driver.IterxI {
+				query = q
+				return &mocks.Iterx{
+					CqlQuery: q,
+					MockPageState: func() []byte {
+						return nextPageState
+					},
+				}
+			}
+			return t
+		}
+
+		params := func(stmtData string) SelectParams {
+			return SelectParams{
+				ColumnValues: []ColumnComparator{
+					Comparator("data", stmtData).Eq(),
+				},
+				AllowFiltering: true,
+				PageSize:       10,
+				ResultsBind:    &[]map[string]interface{}{},
+			}
+		}
+
+		BeforeEach(func() {
+			session = &mocks.Session{}
+			var err error
+			keyspace, err = NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			table = newTable(nil)
+			query = nil
+			nextPageState = []byte("page-2")
+		})
+
+		It("should select first page with page-size", func() {
+			_, cursor, err := table.SelectPage(params("d"), "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cursor).ToNot(BeEmpty())
+			Expect(query.GetPageSize()).To(Equal(uint(10)))
+			Expect(query.GetPageState()).To(BeNil())
+		})
+
+		It("should select next page using the returned cursor", func() {
+			_, cursor, err := table.SelectPage(params("d"), "")
+			Expect(err).ToNot(HaveOccurred())
+
+			nextPageState = nil
+			_, cursor, err = table.SelectPage(params("d"), cursor)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(query.GetPageState()).To(Equal([]byte("page-2")))
+			// No more pages
+			Expect(cursor).To(BeEmpty())
+		})
+
+		It("should verify signed cursors", func() {
+			table = newTable([]byte("secret"))
+			_, cursor, err := table.SelectPage(params("d"), "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cursor).To(ContainSubstring("."))
+
+			_, _, err = table.SelectPage(params("d"), cursor)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(query.GetPageState()).To(Equal([]byte("page-2")))
+
+			// Cursor from a different query
+			_, _, err = table.SelectPage(params("other"), cursor)
+			Expect(err).To(Equal(ErrInvalidCursor))
+
+			// Cursor signed with different key
+			_, _, err = newTable([]byte("other-secret")).SelectPage(params("d"), cursor)
+			Expect(err).To(Equal(ErrInvalidCursor))
+		})
+
+		It("should return ErrInvalidCursor on malformed cursors", func() {
+			_, unsigned, err := table.SelectPage(params("d"), "")
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, cursor := range []string{"!!invalid", "a.b", unsigned + "x.y"} {
+				_, _, err = table.SelectPage(params("d"), cursor)
+				Expect(err).To(Equal(ErrInvalidCursor), cursor)
+			}
+
+			// Unsigned cursor when signatures are required
+			_, _, err = newTable([]byte("secret")).SelectPage(params("d"), unsigned)
+			Expect(err).To(Equal(ErrInvalidCursor))
+		})
+
+		It("should return error if PageSize is not set", func() {
+			p := params("d")
+			p.PageSize = 0
+			_, _, err := table.SelectPage(p, "")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return error if query fails", func() {
+			table.initIterx = func(q driver.QueryI) driver.IterxI {
+				return &mocks.Iterx{
+					CqlQuery: q,
+					MockClose: func() error {
+						return errors.New("some-error")
+					},
+					MockPageState: func() []byte {
+						return nextPageState
+					},
+				}
+			}
+			_, cursor, err := table.SelectPage(params("d"), "")
+			Expect(err).To(HaveOccurred())
+			Expect(cursor).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,410 @@
+package cassandra
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	cql "github.com/gocql/gocql"
+	cqlx "github.com/scylladb/gocqlx"
+	"github.com/scylladb/gocqlx/reflectx"
+)
+
+const defaultPartitionConcurrency = 4
+
+// mergeableTypes are the clustering-column data-types supported
+// for merging the results of #SelectPartitions, see #compareValues.
+var mergeableTypes = map[string]bool{
+	"ascii":     true,
+	"bigint":    true,
+	"blob":      true,
+	"boolean":   true,
+	"date":      true,
+	"double":    true,
+	"float":     true,
+	"int":       true,
+	"smallint":  true,
+	"text":      true,
+	"time":      true,
+	"timestamp": true,
+	"timeuuid":  true,
+	"tinyint":   true,
+	"uuid":      true,
+	"varchar":   true,
+}
+
+// Partition identifies a partition by its partition-key values,
+// with column-names (as in database) as keys.
+type Partition map[string]interface{}
+
+// SelectPartitions gets data from multiple partitions, by running a query
+// per partition concurrently (limited by SelectParams.Concurrency), instead
+// of an IN restriction on partition-key, which loads a single coordinator.
+// The SelectParams.ColumnValues can only restrict clustering-columns (the
+// partition-key restrictions are added from partitions). The results from
+// all partitions are merged in clustering-order (or reversed, as per
+// SelectParams.OrderBy), and SelectParams.Limit is applied to merged results.
+// The SelectParams.ResultsBind must be a pointer to slice of structs or maps,
+// and the clustering-columns must be selected (into struct-fields, for
+// structs) for merging the results. Clustering-columns of types such as
+// varint, decimal, inet or tuples are not supported.
+func (t *Table) SelectPartitions(
+	ctx context.Context,
+	partitions []Partition,
+	p SelectParams,
+) (interface{}, error) {
+	resultsValue := reflect.ValueOf(p.ResultsBind)
+	if resultsValue.Kind() != reflect.Ptr || resultsValue.Elem().Kind() != reflect.Slice {
+		return nil, errors.New("ResultsBind must be a pointer to slice")
+	}
+	err := t.validateSelectPartitions(partitions, p)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := int(p.Concurrency)
+	if concurrency == 0 {
+		concurrency = defaultPartitionConcurrency
+	}
+	queryCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sliceType := resultsValue.Elem().Type()
+	partitionResults := make([]reflect.Value, len(partitions))
+	semaphore := make(chan struct{}, concurrency)
+	errChan := make(chan error, len(partitions))
+	var wg sync.WaitGroup
+
+queryLoop:
+	for i, partition := range partitions {
+		select {
+		case semaphore <- struct{}{}:
+		case <-queryCtx.Done():
+			break queryLoop
+		}
+		wg.Add(1)
+		go func(i int, partition Partition) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+			results := reflect.New(sliceType)
+			err := t.selectPartition(queryCtx, partition, p, results.Interface())
+			if err != nil {
+				errChan <- err
+				cancel()
+				return
+			}
+			partitionResults[i] = results.Elem()
+		}(i, partition)
+	}
+	wg.Wait()
+	close(errChan)
+
+	err = <-errChan
+	if err != nil {
+		return nil, err
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	merged, err := t.mergeResults(sliceType, partitionResults, p.OrderBy, p.Limit)
+	if err != nil {
+		return nil, err
+	}
+	resultsValue.Elem().Set(merged)
+	return p.ResultsBind, nil
+}
+
+// selectPartition gets data from a single partition into resultsBind.
+func (t *Table) selectPartition(
+	ctx context.Context,
+	partition Partition,
+	p SelectParams,
+	resultsBind interface{},
+) error {
+	ccs := []ColumnComparator{}
+	for _, column := range t.PartitionKeyColumns() {
+		ccs = append(ccs, Comparator(column, partition[column]).Eq())
+	}
+	p.ColumnValues = append(ccs, p.ColumnValues...)
+
+	stmt, values, err := t.selectStatement(p)
+	if err != nil {
+		return err
+	}
+	q := t.Session().Query(stmt, values...).WithContext(ctx)
+	if p.PageSize != 0 {
+		q.SetPageSize(p.PageSize)
+	}
+
+	i := t.initIterx(q)
+	err = i.Select(resultsBind)
+	if err != nil {
+		i.Close()
+		return err
+	}
+	return i.Close()
+}
+
+// validateSelectPartitions checks that partitions have values for all
+// partition-key columns, and the params (including ResultsBind) are
+// supported for merging results.
+func (t *Table) validateSelectPartitions(partitions []Partition, p SelectParams) error {
+	if p.Distinct || len(p.GroupBy) > 0 {
+		return errors.New("DISTINCT and GROUP BY are not supported by SelectPartitions")
+	}
+	// Merging results depends on OrderBy matching the clustering-columns
+	err := t.validateOrderBy(p.OrderBy)
+	if err != nil {
+		return err
+	}
+	partitionKey := t.PartitionKeyColumns()
+	for _, partition := range partitions {
+		if len(partition) != len(partitionKey) {
+			return fmt.Errorf(
+				"Partitions must only have values for partition-key columns: (%s)",
+				strings.Join(partitionKey, ", "),
+			)
+		}
+		for _, column := range partitionKey {
+			if _, exists := partition[column]; !exists {
+				return fmt.Errorf("Partition is missing value for column %s", column)
+			}
+		}
+	}
+	for _, cc := range p.ColumnValues {
+		if cc.token || isPrimaryKey(partitionKey, cc.Name) {
+			return fmt.Errorf(
+				"Partition-key cannot be restricted by ColumnValues in SelectPartitions."+
+					" Errored Key: \"%s\"",
+				cc.displayName(),
+			)
+		}
+	}
+
+	for _, column := range t.ClusteringColumns() {
+		dataType := strings.ToLower(strings.TrimSpace(column.DataType))
+		if !mergeableTypes[dataType] {
+			return fmt.Errorf(
+				"Clustering-column %s of type %s is not supported for merging results",
+				column.Name,
+				column.DataType,
+			)
+		}
+	}
+
+	rowType := reflect.TypeOf(p.ResultsBind).Elem().Elem()
+	if rowType.Kind() == reflect.Ptr {
+		rowType = rowType.Elem()
+	}
+	if rowType.Kind() == reflect.Struct {
+		fields := cqlx.DefaultMapper.TypeMap(rowType).Names
+		for _, column := range t.ClusteringColumns() {
+			if _, exists := fields[column.Name]; !exists {
+				return fmt.Errorf(
+					"ResultsBind has no field for clustering-column %s", column.Name,
+				)
+			}
+		}
+	}
+
+	if len(p.SelectColumns) == 0 && len(p.SelectExpressions) == 0 {
+		return nil
+	}
+	for _, column := range t.ClusteringColumns() {
+		if !selectsColumn(p, column.Name) {
+			return fmt.Errorf(
+				"Clustering-column %s must be selected for merging results", column.Name,
+			)
+		}
+	}
+	return nil
+}
+
+// selectsColumn checks if column is selected as is (without alias) in
+// SelectColumns or SelectExpressions.
+func selectsColumn(p SelectParams, column string) bool {
+	for _, col := range p.SelectColumns {
+		if col == column {
+			return true
+		}
+	}
+	for _, se := range p.SelectExpressions {
+		if se.alias == "" && se.cql == QuoteIdentifier(column) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeResults merges the results (slices of sliceType) in clustering-order,
+// reversed if orderBy reverses the clustering-order. The results are
+// expected to be individually ordered. Returns at most limit rows,
+// if limit is not zero.
+func (t *Table) mergeResults(
+	sliceType reflect.Type,
+	results []reflect.Value,
+	orderBy []ColumnOrder,
+	limit uint,
+) (reflect.Value, error) {
+	merged := reflect.MakeSlice(sliceType, 0, 0)
+	for _, result := range results {
+		if result.IsValid() {
+			merged = reflect.AppendSlice(merged, result)
+		}
+	}
+
+	clusteringColumns := t.ClusteringColumns()
+	reversed := len(orderBy) > 0 &&
+		orderBy[0].order() != clusteringOrder(clusteringColumns[0])
+	var cmpErr error
+	sort.SliceStable(merged.Interface(), func(i, j int) bool {
+		a := merged.Index(i)
+		b := merged.Index(j)
+		for _, column := range clusteringColumns {
+			cmp, err := compareValues(rowValue(a, column.Name), rowValue(b, column.Name))
+			if err != nil {
+				if cmpErr == nil {
+					cmpErr = fmt.Errorf("Clustering-column %s: %s", column.Name, err)
+				}
+				return false
+			}
+			if cmp == 0 {
+				continue
+			}
+			if (clusteringOrder(column) == "DESC") != reversed {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+	if cmpErr != nil {
+		return reflect.Value{}, cmpErr
+	}
+
+	if limit != 0 && merged.Len() > int(limit) {
+		merged = merged.Slice(0, int(limit))
+	}
+	return merged, nil
+}
+
+// rowValue returns the value of column from a row (struct or map),
+// or nil if row doesn't have the column.
+func rowValue(row reflect.Value, column string) interface{} {
+	for row.Kind() == reflect.Ptr || row.Kind() == reflect.Interface {
+		if row.IsNil() {
+			return nil
+		}
+		row = row.Elem()
+	}
+
+	var value reflect.Value
+	switch row.Kind() {
+	case reflect.Map:
+		value = row.MapIndex(reflect.ValueOf(column))
+	case reflect.Struct:
+		fi, exists := cqlx.DefaultMapper.TypeMap(row.Type()).Names[column]
+		if !exists {
+			return nil
+		}
+		value = reflectx.FieldByIndexesReadOnly(row, fi.Index)
+	}
+	if !value.IsValid() {
+		return nil
+	}
+	return value.Interface()
+}
+
+// compareValues compares the column-values, and returns -1, 0 or 1 if a is
+// less than, equal to, or greater than b. The timeuuids are compared by
+// their time. Nil values are less than other values. An error is returned
+// for values of different or unsupported types.
+func compareValues(a interface{}, b interface{}) (int, error) {
+	va := reflect.Indirect(reflect.ValueOf(a))
+	vb := reflect.Indirect(reflect.ValueOf(b))
+	if !va.IsValid() || !vb.IsValid() {
+		return compareBool(va.IsValid(), vb.IsValid()), nil
+	}
+	if va.Type() != vb.Type() {
+		return 0, fmt.Errorf("Cannot compare values of types %s and %s", va.Type(), vb.Type())
+	}
+	a = va.Interface()
+	b = vb.Interface()
+
+	switch av := a.(type) {
+	case time.Time:
+		bv := b.(time.Time)
+		if av.Before(bv) {
+			return -1, nil
+		}
+		if av.After(bv) {
+			return 1, nil
+		}
+		return 0, nil
+	case cql.UUID:
+		bv := b.(cql.UUID)
+		if av.Version() == 1 && bv.Version() == 1 && av.Timestamp() != bv.Timestamp() {
+			return compareInt(av.Timestamp(), bv.Timestamp()), nil
+		}
+		return bytes.Compare(av.Bytes(), bv.Bytes()), nil
+	case []byte:
+		return bytes.Compare(av, b.([]byte)), nil
+	}
+
+	switch va.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareInt(va.Int(), vb.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		ua, ub := va.Uint(), vb.Uint()
+		if ua == ub {
+			return 0, nil
+		}
+		if ua < ub {
+			return -1, nil
+		}
+		return 1, nil
+	case reflect.Float32, reflect.Float64:
+		fa, fb := va.Float(), vb.Float()
+		if fa == fb {
+			return 0, nil
+		}
+		if fa < fb {
+			return -1, nil
+		}
+		return 1, nil
+	case reflect.String:
+		return strings.Compare(va.String(), vb.String()), nil
+	case reflect.Bool:
+		return compareBool(va.Bool(), vb.Bool()), nil
+	}
+	return 0, fmt.Errorf("Cannot compare values of type %s", va.Type())
+}
+
+func compareInt(a int64, b int64) int {
+	if a == b {
+		return 0
+	}
+	if a < b {
+		return -1
+	}
+	return 1
+}
+
+// compareBool orders false before true.
+func compareBool(a bool, b bool) int {
+	if a == b {
+		return 0
+	}
+	if !a {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,373 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	cql "github.com/gocql/gocql"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Table", func() {
+	Context("data is selected from multiple partitions", func() {
+		type event struct {
+			Tenant     string    `db:"tenant"`
+			YearBucket int       `db:"year_bucket"`
+			Timestamp  time.Time `db:"timestamp"`
+			ID         int       `db:"id"`
+		}
+
+		var (
+			table       *Table
+			data        map[int][]event
+			selectError error
+		)
+
+		ts := func(day int) time.Time {
+			return time.Date(2018, 1, day, 0, 0, 0, 0, time.UTC)
+		}
+		partitions := []Partition{
+			Partition{"tenant": "t1", "year_bucket": 2017},
+			Partition{"tenant": "t1", "year_bucket": 2018},
+		}
+
+		BeforeEach(func() {
+			session := &mocks.Session{}
+			keyspace, err := NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			table, err = NewTable(session, &TableConfig{
+				Keyspace: keyspace,
+				Name:     "test_table",
+			}, &map[string]TableColumn{
+				"tenant": TableColumn{
+					Name:              "tenant",
+					DataType:          "text",
+					PrimaryKeyIndex:   "0",
+					PartitionKeyIndex: "0",
+				},
+				"yearBucket": TableColumn{
+					Name:              "year_bucket",
+					DataType:          "int",
+					PrimaryKeyIndex:   "0",
+					PartitionKeyIndex: "1",
+				},
+				"timestamp": TableColumn{
+					Name:            "timestamp",
+					DataType:        "timestamp",
+					PrimaryKeyIndex: "1",
+					PrimaryKeyOrder: "DESC",
+				},
+				"id": TableColumn{
+					Name:            "id",
+					DataType:        "int",
+					PrimaryKeyIndex: "2",
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			// Partitions are in clustering-order (timestamp DESC, id ASC)
+			data = map[int][]event{
+				2017: []event{
+					{"t1", 2017, ts(5), 1},
+					{"t1", 2017, ts(3), 2},
+					{"t1", 2017, ts(1), 1},
+				},
+				2018: []event{
+					{"t1", 2018, ts(4), 1},
+					{"t1", 2018, ts(3), 1},
+					{"t1", 2018, ts(2), 1},
+				},
+			}
+			selectError = nil
+			table.initIterx = func(q driver.QueryI) driver.IterxI {
+				// Partition-key values are first bind-values
+				yearBucket := q.(*mocks.Query).Values()[1].(int)
+				return &mocks.Iterx{
+					CqlQuery: q,
+					MockSelect: func(dest interface{}) error {
+						if selectError != nil {
+							return selectError
+						}
+						*dest.(*[]event) = data[yearBucket]
+						return nil
+					},
+				}
+			}
+		})
+
+		It("should merge the results in clustering-order", func() {
+			results := []event{}
+			_, err := table.SelectPartitions(context.Background(), partitions, SelectParams{
+				ResultsBind: &results,
+				Concurrency: 1,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(Equal([]event{
+				{"t1", 2017, ts(5), 1},
+				{"t1", 2018, ts(4), 1},
+				{"t1", 2018, ts(3), 1},
+				{"t1", 2017, ts(3), 2},
+				{"t1", 2018, ts(2), 1},
+				{"t1", 2017, ts(1), 1},
+			}))
+		})
+
+		It("should merge in reversed order and apply limit", func() {
+			// Reverse the partition-results, as returned for reversed ORDER BY
+			for bucket, events := range data {
+				reversed := []event{}
+				for i := len(events) - 1; i >= 0; i-- {
+					reversed = append(reversed, events[i])
+				}
+				data[bucket] = reversed
+			}
+
+			results := []event{}
+			_, err := table.SelectPartitions(context.Background(), partitions, SelectParams{
+				ColumnValues: []ColumnComparator{Comparator("timestamp", ts(0)).Gt()},
+				OrderBy:      []ColumnOrder{Asc("timestamp"), Desc("id")},
+				Limit:        3,
+				ResultsBind:  &results,
+				Concurrency:  1,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			// The mocked results are not filtered by ColumnValues
+			Expect(results).To(Equal([]event{
+				{"t1", 2017, ts(1), 1},
+				{"t1", 2018, ts(2), 1},
+				{"t1", 2017, ts(3), 2},
+			}))
+		})
+
+		It("should merge map results", func() {
+			table.initIterx = func(q driver.QueryI) driver.IterxI {
+				yearBucket := q.(*mocks.Query).Values()[1].(int)
+				return &mocks.Iterx{
+					CqlQuery: q,
+					MockSelect: func(dest interface{}) error {
+						rows := []map[string]interface{}{}
+						for _, e := range data[yearBucket] {
+							rows = append(rows, map[string]interface{}{
+								"timestamp": e.Timestamp,
+								"id":        e.ID,
+							})
+						}
+						*dest.(*[]map[string]interface{}) = rows
+						return nil
+					},
+				}
+			}
+
+			results := []map[string]interface{}{}
+			_, err := table.SelectPartitions(context.Background(), partitions, SelectParams{
+				ResultsBind: &results,
+				Concurrency: 1,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(results).To(HaveLen(6))
+			Expect(results[1]["timestamp"]).To(Equal(ts(4)))
+		})
+
+		It("should limit the concurrent queries", func() {
+			var lock sync.Mutex
+			inFlight := 0
+			maxInFlight := 0
+			table.initIterx = func(q driver.QueryI) driver.IterxI {
+				return &mocks.Iterx{
+					CqlQuery: q,
+					MockSelect: func(dest interface{}) error {
+						lock.Lock()
+						inFlight++
+						if inFlight > maxInFlight {
+							maxInFlight = inFlight
+						}
+						lock.Unlock()
+
+						time.Sleep(5 * time.Millisecond)
+						lock.Lock()
+						inFlight--
+						lock.Unlock()
+						return nil
+					},
+				}
+			}
+
+			manyPartitions := []Partition{}
+			for i := 0; i < 10; i++ {
+				manyPartitions = append(manyPartitions, Partition{
+					"tenant":      "t1",
+					"year_bucket": i,
+				})
+			}
+			_, err := table.SelectPartitions(context.Background(), manyPartitions, SelectParams{
+				ResultsBind: &[]event{},
+				Concurrency: 3,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(maxInFlight <= 3).To(BeTrue())
+		})
+
+		It("should return query errors", func() {
+			selectError = errors.New("some-error")
+			_, err := table.SelectPartitions(context.Background(), partitions, SelectParams{
+				ResultsBind: &[]event{},
+			})
+			Expect(err).To(Equal(selectError))
+		})
+
+		It("should return error on invalid partitions or params", func() {
+			invalidPartitions := [][]Partition{
+				[]Partition{Partition{"tenant": "t1"}},
+				[]Partition{Partition{"tenant": "t1", "id": 1}},
+			}
+			for _, p := range invalidPartitions {
+				_, err := table.SelectPartitions(context.Background(), p, SelectParams{
+					ResultsBind: &[]event{},
+				})
+				Expect(err).To(HaveOccurred())
+			}
+
+			invalidParams := []SelectParams{
+				SelectParams{ResultsBind: []event{}},
+				SelectParams{
+					ResultsBind:  &[]event{},
+					ColumnValues: []ColumnComparator{Comparator("tenant", "t2").Eq()},
+				},
+				SelectParams{
+					ResultsBind:   &[]event{},
+					SelectColumns: []string{"tenant", "timestamp"},
+				},
+				SelectParams{ResultsBind: &[]event{}, Distinct: true},
+				SelectParams{
+					ResultsBind: &[]event{},
+					OrderBy:     []ColumnOrder{ColumnOrder{Name: "id"}},
+				},
+				// Missing the fields for clustering-columns
+				SelectParams{ResultsBind: &[]struct {
+					Tenant string `db:"tenant"`
+				}{}},
+			}
+			for _, p := range invalidParams {
+				_, err := table.SelectPartitions(context.Background(), partitions, p)
+				Expect(err).To(HaveOccurred())
+			}
+		})
+
+		It("should return error if clustering-columns can't be merged", func() {
+			session := &mocks.Session{}
+			keyspace, err := NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			table, err := NewTable(session, &TableConfig{
+				Keyspace: keyspace,
+				Name:     "balances",
+			}, &map[string]TableColumn{
+				"tenant": TableColumn{
+					Name:            "tenant",
+					DataType:        "text",
+					PrimaryKeyIndex: "0",
+				},
+				"amount": TableColumn{
+					Name:            "amount",
+					DataType:        "varint",
+					PrimaryKeyIndex: "1",
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = table.SelectPartitions(
+				context.Background(),
+				[]Partition{Partition{"tenant": "t1"}},
+				SelectParams{ResultsBind: &[]map[string]interface{}{}},
+			)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("amount"))
+		})
+
+		It("should return error on ORDER BY if table has no clustering-columns", func() {
+			session := &mocks.Session{}
+			keyspace, err := NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			table, err := NewTable(session, &TableConfig{
+				Keyspace: keyspace,
+				Name:     "users",
+			}, &map[string]TableColumn{
+				"tenant": TableColumn{
+					Name:            "tenant",
+					DataType:        "text",
+					PrimaryKeyIndex: "0",
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = table.SelectPartitions(context.Background(), []Partition{}, SelectParams{
+				ResultsBind: &[]event{},
+				OrderBy:     []ColumnOrder{ColumnOrder{Name: "timestamp"}},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("column-values are compared", func() {
+		It("should compare values by type", func() {
+			Expect(compareValues(1, 2)).To(Equal(-1))
+			Expect(compareValues(uint8(2), uint8(1))).To(Equal(1))
+			Expect(compareValues(1.5, 1.5)).To(Equal(0))
+			Expect(compareValues("a", "b")).To(Equal(-1))
+			Expect(compareValues([]byte{2}, []byte{1})).To(Equal(1))
+			Expect(compareValues(nil, 1)).To(Equal(-1))
+			Expect(compareValues(time.Second, time.Minute)).To(Equal(-1))
+
+			a, b := 1, 2
+			Expect(compareValues(&a, &b)).To(Equal(-1))
+		})
+
+		It("should return error for values of different or unsupported types", func() {
+			unsupported := [][]interface{}{
+				[]interface{}{1, "a"},
+				[]interface{}{big.NewInt(1), big.NewInt(2)},
+				[]interface{}{net.ParseIP("10.0.0.1"), net.ParseIP("10.0.0.2")},
+				[]interface{}{[]interface{}{1}, []interface{}{2}},
+			}
+			for _, values := range unsupported {
+				_, err := compareValues(values[0], values[1])
+				Expect(err).To(HaveOccurred())
+			}
+		})
+
+		It("should return nil for columns missing from row", func() {
+			row := struct {
+				ID int `db:"id"`
+			}{1}
+			Expect(rowValue(reflect.ValueOf(row), "id")).To(Equal(1))
+			Expect(rowValue(reflect.ValueOf(&row), "name")).To(BeNil())
+			Expect(rowValue(reflect.ValueOf(map[string]interface{}{}), "id")).To(BeNil())
+		})
+
+		It("should compare timeuuids by time", func() {
+			// Version 1 UUIDs, the first has later time_low but earlier time_hi
+			earlier, err := cql.ParseUUID("ffffffff-0000-1000-8000-000000000000")
+			Expect(err).ToNot(HaveOccurred())
+			later, err := cql.ParseUUID("00000000-0000-1001-8000-000000000000")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(compareValues(earlier, later)).To(Equal(-1))
+		})
+	})
+})
@@ -0,0 +1,280 @@
+package cassandra
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"sync"
+)
+
+const (
+	defaultScanSplits      = 64
+	defaultScanConcurrency = 4
+)
+
+// TokenRange is a range of Murmur3 partition-key tokens,
+// from Start (exclusive) to End (inclusive).
+type TokenRange struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+// ScanProgress is the progress of a #Scan, as reported after
+// each completed token-range.
+type ScanProgress struct {
+	// Includes the ranges completed in previous (checkpointed) scans
+	CompletedRanges int
+	TotalRanges     int
+	// Rows scanned by this scan
+	Rows int64
+}
+
+// ScanCheckpoint stores the token-ranges completed by #Scan, so an
+// interrupted scan can be resumed by skipping those ranges.
+// See FileCheckpoint.
+type ScanCheckpoint interface {
+	CompletedRanges() ([]TokenRange, error)
+	MarkCompleted(r TokenRange) error
+}
+
+// ScanOptions defines parameters for #Scan.
+type ScanOptions struct {
+	// Number of token-ranges to split the token-ring into, defaults to 64.
+	// This must be same when resuming a scan from checkpoint.
+	Splits int
+	// Number of token-ranges queried concurrently, defaults to 4
+	Concurrency int
+	// The columns to add in SELECT statement
+	SelectColumns []string
+	PageSize      uint
+	// Defines the row-type, same as for #Iterate
+	ResultsBind interface{}
+	// Called for each row. This is called concurrently
+	// when Concurrency is more than 1.
+	RowFunc func(row interface{}) error
+	// Called after each completed token-range
+	OnProgress func(p ScanProgress)
+	// If set, the completed token-ranges are stored, and skipped
+	// when scan is resumed.
+	Checkpoint ScanCheckpoint
+}
+
+// SplitTokenRing splits the Murmur3 token-ring into n contiguous
+// ranges of (nearly) equal size.
+func SplitTokenRing(n int) []TokenRange {
+	if n < 1 {
+		n = 1
+	}
+	var minToken int64 = math.MinInt64
+	width := math.MaxUint64 / uint64(n)
+
+	ranges := make([]TokenRange, n)
+	start := minToken
+	for i := range ranges {
+		end := int64(uint64(minToken) + width*uint64(i+1))
+		if i == n-1 {
+			end = math.MaxInt64
+		}
+		ranges[i] = TokenRange{
+			Start: start,
+			End:   end,
+		}
+		start = end
+	}
+	return ranges
+}
+
+// Scan reads the whole table by splitting the token-ring into ranges, and
+// querying the ranges concurrently. The rows are delivered to
+// ScanOptions.RowFunc. The scan stops on first error (from query, RowFunc
+// or checkpoint), or when context is canceled.
+// The rows of a range interrupted before completion are delivered again
+// when the scan is resumed from checkpoint.
+func (t *Table) Scan(ctx context.Context, opts ScanOptions) error {
+	if opts.RowFunc == nil {
+		return errors.New("RowFunc is required for Scan")
+	}
+	if opts.Splits < 0 || opts.Concurrency < 0 {
+		return errors.New("Splits and Concurrency must not be negative")
+	}
+	splits := opts.Splits
+	if splits == 0 {
+		splits = defaultScanSplits
+	}
+	concurrency := opts.Concurrency
+	if concurrency == 0 {
+		concurrency = defaultScanConcurrency
+	}
+
+	ranges := SplitTokenRing(splits)
+	completed := make(map[TokenRange]bool)
+	if opts.Checkpoint != nil {
+		completedRanges, err := opts.Checkpoint.CompletedRanges()
+		if err != nil {
+			return err
+		}
+		for _, r := range completedRanges {
+			completed[r] = true
+		}
+	}
+	pending := []TokenRange{}
+	for _, r := range ranges {
+		if !completed[r] {
+			pending = append(pending, r)
+		}
+	}
+	progress := ScanProgress{
+		CompletedRanges: len(ranges) - len(pending),
+		TotalRanges:     len(ranges),
+	}
+
+	scanCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	rangeChan := make(chan TokenRange)
+	errChan := make(chan error, concurrency)
+	var progressLock sync.Mutex
+	var wg sync.WaitGroup
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range rangeChan {
+				rows, err := t.scanRange(scanCtx, opts, r)
+				if err == nil && opts.Checkpoint != nil {
+					err = opts.Checkpoint.MarkCompleted(r)
+				}
+				if err != nil {
+					errChan <- err
+					cancel()
+					return
+				}
+
+				progressLock.Lock()
+				progress.CompletedRanges++
+				progress.Rows += rows
+				if opts.OnProgress != nil {
+					opts.OnProgress(progress)
+				}
+				progressLock.Unlock()
+			}
+		}()
+	}
+
+feedLoop:
+	for _, r := range pending {
+		select {
+		case rangeChan <- r:
+		case <-scanCtx.Done():
+			break feedLoop
+		}
+	}
+	close(rangeChan)
+	wg.Wait()
+	close(errChan)
+
+	err := <-errChan
+	if err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// scanRange iterates the rows in token-range.
+// Returns the number of rows scanned.
+func (t *Table) scanRange(
+	ctx context.Context,
+	opts ScanOptions,
+	r TokenRange,
+) (int64, error) {
+	partitionKey := t.PartitionKeyColumns()
+	var rows int64
+	err := t.Iterate(ctx, SelectParams{
+		ColumnValues: []ColumnComparator{
+			TokenValueComparator(partitionKey, r.Start).Gt(),
+			TokenValueComparator(partitionKey, r.End).LtOrEq(),
+		},
+		SelectColumns: opts.SelectColumns,
+		PageSize:      opts.PageSize,
+		ResultsBind:   opts.ResultsBind,
+	}, func(row interface{}) error {
+		rows++
+		return opts.RowFunc(row)
+	})
+	return rows, err
+}
+
+// FileCheckpoint is a ScanCheckpoint which stores the
+// completed token-ranges as JSON in a file.
+type FileCheckpoint struct {
+	lock   sync.Mutex
+	loaded bool
+	path   string
+	ranges []TokenRange
+}
+
+// NewFileCheckpoint creates a FileCheckpoint using the file at path.
+// The file is created when first range is completed.
+func NewFileCheckpoint(path string) *FileCheckpoint {
+	return &FileCheckpoint{
+		path: path,
+	}
+}
+
+// CompletedRanges returns the token-ranges stored in file.
+func (fc *FileCheckpoint) CompletedRanges() ([]TokenRange, error) {
+	fc.lock.Lock()
+	defer fc.lock.Unlock()
+
+	err := fc.load()
+	if err != nil {
+		return nil, err
+	}
+	return append([]TokenRange{}, fc.ranges...), nil
+}
+
+// MarkCompleted adds the token-range to file. The file is replaced
+// atomically, so it isn't corrupted if process crashes while writing.
+func (fc *FileCheckpoint) MarkCompleted(r TokenRange) error {
+	fc.lock.Lock()
+	defer fc.lock.Unlock()
+
+	err := fc.load()
+	if err != nil {
+		return err
+	}
+	fc.ranges = append(fc.ranges, r)
+	data, err := json.Marshal(fc.ranges)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := fc.path + ".tmp"
+	err = ioutil.WriteFile(tmpPath, data, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, fc.path)
+}
+
+// load reads the stored token-ranges from file, if not already loaded.
+func (fc *FileCheckpoint) load() error {
+	if fc.loaded {
+		return nil
+	}
+	data, err := ioutil.ReadFile(fc.path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(data) > 0 {
+		err = json.Unmarshal(data, &fc.ranges)
+		if err != nil {
+			return err
+		}
+	}
+	fc.loaded = true
+	return nil
+}
@@ -0,0 +1,235 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	"github.com/TerrexTech/go-commonutils/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// memoryCheckpoint is an in-memory ScanCheckpoint.
+type memoryCheckpoint struct {
+	lock   sync.Mutex
+	ranges []TokenRange
+}
+
+func (mc *memoryCheckpoint) CompletedRanges() ([]TokenRange, error) {
+	return mc.ranges, nil
+}
+
+func (mc *memoryCheckpoint) MarkCompleted(r TokenRange) error {
+	mc.lock.Lock()
+	defer mc.lock.Unlock()
+	mc.ranges = append(mc.ranges, r)
+	return nil
+}
+
+var _ = Describe("Table", func() {
+	Context("table is scanned by token-ranges", func() {
+		var (
+			lock    sync.Mutex
+			stmts   []string
+			values  [][]interface{}
+			table   *Table
+			rowFunc func(row interface{}) error
+		)
+
+		BeforeEach(func() {
+			session := &mocks.Session{
+				MockQuery: func(s string, v ...interface{}) {
+					lock.Lock()
+					defer lock.Unlock()
+					stmts = append(stmts, utils.StandardizeSpaces(s))
+					values = append(values, v)
+				},
+			}
+			keyspace, err := NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			table, err = NewTable(session, &TableConfig{
+				Keyspace: keyspace,
+				Name:     "test_table",
+			}, &map[string]TableColumn{
+				"tenant": TableColumn{
+					Name:              "tenant",
+					DataType:          "text",
+					PrimaryKeyIndex:   "0",
+					PartitionKeyIndex: "0",
+				},
+				"id": TableColumn{
+					Name:              "id",
+					DataType:          "int",
+					PrimaryKeyIndex:   "0",
+					PartitionKeyIndex: "1",
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			stmts = []string{}
+			values = [][]interface{}{}
+
+			// Each range has two rows
+			table.initIterx = func(q driver.QueryI) driver.IterxI {
+				scanned := 0
+				return &mocks.Iterx{
+					CqlQuery: q,
+					MockMapScan: func(m map[string]interface{}) bool {
+						scanned++
+						return scanned <= 2
+					},
+				}
+			}
+			rowFunc = func(row interface{}) error {
+				return nil
+			}
+		})
+
+		It("should split the token-ring into contiguous ranges", func() {
+			ranges := SplitTokenRing(3)
+			Expect(ranges).To(HaveLen(3))
+			Expect(ranges[0].Start).To(Equal(int64(math.MinInt64)))
+			Expect(ranges[2].End).To(Equal(int64(math.MaxInt64)))
+			for i := 1; i < len(ranges); i++ {
+				Expect(ranges[i].Start).To(Equal(ranges[i-1].End))
+				Expect(ranges[i].Start < ranges[i].End).To(BeTrue())
+			}
+
+			Expect(SplitTokenRing(1)).To(Equal([]TokenRange{
+				TokenRange{Start: math.MinInt64, End: math.MaxInt64},
+			}))
+		})
+
+		It("should query all token-ranges and report progress", func() {
+			var rowLock sync.Mutex
+			rows := 0
+			progress := []ScanProgress{}
+			err := table.Scan(context.Background(), ScanOptions{
+				Splits:      8,
+				Concurrency: 3,
+				RowFunc: func(row interface{}) error {
+					rowLock.Lock()
+					defer rowLock.Unlock()
+					rows++
+					return nil
+				},
+				OnProgress: func(p ScanProgress) {
+					progress = append(progress, p)
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rows).To(Equal(16))
+			Expect(progress).To(HaveLen(8))
+			Expect(progress[7]).To(Equal(ScanProgress{
+				CompletedRanges: 8,
+				TotalRanges:     8,
+				Rows:            16,
+			}))
+
+			Expect(stmts).To(HaveLen(8))
+			Expect(stmts[0]).To(Equal(
+				"SELECT * FROM test.test_table WHERE TOKEN(tenant,id)>? AND TOKEN(tenant,id)<=?",
+			))
+			tokens := map[int64]int64{}
+			for _, v := range values {
+				tokens[v[0].(int64)] = v[1].(int64)
+			}
+			for _, r := range SplitTokenRing(8) {
+				Expect(tokens[r.Start]).To(Equal(r.End))
+			}
+		})
+
+		It("should skip the checkpointed ranges and checkpoint completed ranges", func() {
+			ranges := SplitTokenRing(4)
+			checkpoint := &memoryCheckpoint{
+				ranges: []TokenRange{ranges[0], ranges[2]},
+			}
+			var lastProgress ScanProgress
+			err := table.Scan(context.Background(), ScanOptions{
+				Splits:     4,
+				RowFunc:    rowFunc,
+				Checkpoint: checkpoint,
+				OnProgress: func(p ScanProgress) {
+					lastProgress = p
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stmts).To(HaveLen(2))
+			Expect(checkpoint.ranges).To(ConsistOf(ranges))
+			Expect(lastProgress.CompletedRanges).To(Equal(4))
+			Expect(lastProgress.Rows).To(Equal(int64(4)))
+		})
+
+		It("should stop on first error", func() {
+			checkpoint := &memoryCheckpoint{}
+			err := table.Scan(context.Background(), ScanOptions{
+				Splits:      16,
+				Concurrency: 1,
+				RowFunc: func(row interface{}) error {
+					return errors.New("some-error")
+				},
+				Checkpoint: checkpoint,
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("some-error"))
+			Expect(stmts).To(HaveLen(1))
+			Expect(checkpoint.ranges).To(BeEmpty())
+
+			err = table.Scan(context.Background(), ScanOptions{})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return error if Splits or Concurrency is negative", func() {
+			err := table.Scan(context.Background(), ScanOptions{
+				Concurrency: -1,
+				RowFunc:     rowFunc,
+			})
+			Expect(err).To(HaveOccurred())
+
+			err = table.Scan(context.Background(), ScanOptions{
+				Splits:  -1,
+				RowFunc: rowFunc,
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(stmts).To(BeEmpty())
+		})
+
+		It("should stop if context is canceled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			err := table.Scan(ctx, ScanOptions{
+				RowFunc: rowFunc,
+			})
+			Expect(err).To(Equal(context.Canceled))
+		})
+
+		It("should store checkpoints in file", func() {
+			dir, err := ioutil.TempDir("", "scan")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.RemoveAll(dir)
+			path := filepath.Join(dir, "checkpoint.json")
+
+			ranges := SplitTokenRing(2)
+			checkpoint := NewFileCheckpoint(path)
+			completed, err := checkpoint.CompletedRanges()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(completed).To(BeEmpty())
+			Expect(checkpoint.MarkCompleted(ranges[1])).To(Succeed())
+
+			// Resumed checkpoint
+			completed, err = NewFileCheckpoint(path).CompletedRanges()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(completed).To(Equal([]TokenRange{ranges[1]}))
+		})
+	})
+})
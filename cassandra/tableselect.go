@@ -0,0 +1,368 @@
+package cassandra
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ColumnOrder defines a column in ORDER BY clause of SELECT query.
+type ColumnOrder struct {
+	Name string
+	// ASC or DESC, defaults to ASC
+	Order string
+}
+
+// Asc creates ascending ColumnOrder for column.
+func Asc(col string) ColumnOrder {
+	return ColumnOrder{
+		Name:  col,
+		Order: "ASC",
+	}
+}
+
+// Desc creates descending ColumnOrder for column.
+func Desc(col string) ColumnOrder {
+	return ColumnOrder{
+		Name:  col,
+		Order: "DESC",
+	}
+}
+
+// selectStatement validates the SelectParams, and builds
+// the SELECT statement and its bind-values.
+func (t *Table) selectStatement(p SelectParams) (string, []interface{}, error) {
+	err := t.validateSelect(p)
+	if err != nil {
+		return "", nil, err
+	}
+
+	stmt := "SELECT "
+	selectColumns := p.SelectColumns
+	if p.Distinct {
+		stmt += "DISTINCT "
+		if len(selectColumns) == 0 && len(p.SelectExpressions) == 0 {
+			selectColumns = t.PartitionKeyColumns()
+		}
+	}
+	selectClause := quoteIdentifiers(selectColumns)
+	for _, se := range p.SelectExpressions {
+		selectClause = append(selectClause, se.toCql())
+	}
+	if len(selectClause) == 0 {
+		stmt += "*"
+	} else {
+		stmt += strings.Join(selectClause, ",")
+	}
+	stmt += fmt.Sprintf(" FROM %s ", t.FullName())
+
+	where, values, err := whereClause(p.ColumnValues)
+	if err != nil {
+		return "", nil, err
+	}
+	stmt += where
+
+	if len(p.GroupBy) > 0 {
+		stmt += fmt.Sprintf("GROUP BY %s ", strings.Join(quoteIdentifiers(p.GroupBy), ","))
+	}
+	if len(p.OrderBy) > 0 {
+		orderBy := make([]string, len(p.OrderBy))
+		for i, co := range p.OrderBy {
+			orderBy[i] = fmt.Sprintf("%s %s", QuoteIdentifier(co.Name), co.order())
+		}
+		stmt += fmt.Sprintf("ORDER BY %s ", strings.Join(orderBy, ","))
+	}
+	if p.PerPartitionLimit != 0 {
+		stmt += fmt.Sprintf("PER PARTITION LIMIT %d ", p.PerPartitionLimit)
+	}
+	if p.Limit != 0 {
+		stmt += fmt.Sprintf("LIMIT %d ", p.Limit)
+	}
+	if p.AllowFiltering {
+		stmt += "ALLOW FILTERING "
+	}
+	return stmt, values, nil
+}
+
+// Count returns the number of rows matching the WHERE clause of SelectParams.
+// The SelectColumns, SelectExpressions, Distinct, GroupBy, OrderBy and
+// ResultsBind params are ignored.
+func (t *Table) Count(p SelectParams) (int64, error) {
+	p.SelectColumns = nil
+	p.SelectExpressions = []SelectExpression{CountAll().As("count")}
+	p.Distinct = false
+	p.GroupBy = nil
+	p.OrderBy = nil
+
+	results := []struct {
+		Count int64 `db:"count"`
+	}{}
+	p.ResultsBind = &results
+	_, err := t.Select(p)
+	if err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+	return results[0].Count, nil
+}
+
+// whereClause builds the WHERE clause from comparators.
+// Returns blank clause if no comparators are provided.
+func whereClause(ccs []ColumnComparator) (string, []interface{}, error) {
+	if len(ccs) == 0 {
+		return "", []interface{}{}, nil
+	}
+
+	conditions := make([]string, len(ccs))
+	values := []interface{}{}
+	for i, cc := range ccs {
+		condition, ccValues, err := cc.toCql()
+		if err != nil {
+			return "", nil, err
+		}
+		conditions[i] = condition
+		values = append(values, ccValues...)
+	}
+	return fmt.Sprintf("WHERE %s ", strings.Join(conditions, " AND ")), values, nil
+}
+
+// toCql returns the WHERE condition and its bind-values.
+// The tuple and token comparisons have a bind-value for each column.
+func (cc ColumnComparator) toCql() (string, []interface{}, error) {
+	if cc.operator == "" {
+		return "", nil, fmt.Errorf(
+			"No comparison-operator set for column \"%s\"", cc.displayName(),
+		)
+	}
+	if cc.columns == nil {
+		condition := fmt.Sprintf("%s%s?", QuoteIdentifier(cc.Name), cc.operator)
+		return condition, []interface{}{cc.Value}, nil
+	}
+
+	if len(cc.columns) == 0 {
+		return "", nil, errors.New("Tuple and token comparators require columns")
+	}
+	columns := strings.Join(quoteIdentifiers(cc.columns), ",")
+	if cc.rawToken {
+		if !isRangeOperator(cc.operator) && cc.operator != "=" {
+			return "", nil, fmt.Errorf(
+				"Operator \"%s\" is not supported for %s",
+				strings.TrimSpace(cc.operator), cc.displayName(),
+			)
+		}
+		condition := fmt.Sprintf("TOKEN(%s)%s?", columns, cc.operator)
+		return condition, []interface{}{cc.Value}, nil
+	}
+	values, _ := cc.Value.([]interface{})
+
+	switch cc.operator {
+	case "=", "<", "<=", ">", ">=":
+		if len(values) != len(cc.columns) {
+			return "", nil, fmt.Errorf(
+				"Expected %d values for %s, found %d",
+				len(cc.columns), cc.displayName(), len(values),
+			)
+		}
+		if cc.token {
+			condition := fmt.Sprintf(
+				"TOKEN(%s)%sTOKEN(%s)", columns, cc.operator, placeholders(len(values)),
+			)
+			return condition, values, nil
+		}
+		condition := fmt.Sprintf(
+			"(%s)%s(%s)", columns, cc.operator, placeholders(len(values)),
+		)
+		return condition, values, nil
+
+	case " IN ":
+		if cc.token || len(values) == 0 {
+			break
+		}
+		tuples := make([]string, len(values))
+		tupleValues := []interface{}{}
+		for i, value := range values {
+			tuple, ok := value.([]interface{})
+			if !ok || len(tuple) != len(cc.columns) {
+				return "", nil, fmt.Errorf(
+					"Each IN value for %s must be a tuple of %d values",
+					cc.displayName(), len(cc.columns),
+				)
+			}
+			tuples[i] = fmt.Sprintf("(%s)", placeholders(len(tuple)))
+			tupleValues = append(tupleValues, tuple...)
+		}
+		condition := fmt.Sprintf("(%s) IN (%s)", columns, strings.Join(tuples, ","))
+		return condition, tupleValues, nil
+	}
+	return "", nil, fmt.Errorf(
+		"Operator \"%s\" is not supported for %s",
+		strings.TrimSpace(cc.operator), cc.displayName(),
+	)
+}
+
+// displayName returns the column-name, or the tuple/token
+// columns, as used in error-messages.
+func (cc ColumnComparator) displayName() string {
+	if cc.columns == nil {
+		return cc.Name
+	}
+	name := fmt.Sprintf("(%s)", strings.Join(cc.columns, ", "))
+	if cc.token {
+		return "TOKEN" + name
+	}
+	return name
+}
+
+// placeholders returns n comma-separated bind-markers.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// validateSelect checks the WHERE, ORDER BY, DISTINCT and GROUP BY
+// parameters against table-definition.
+func (t *Table) validateSelect(p SelectParams) error {
+	if !p.SkipRestrictionValidation {
+		err := t.validateSelectRestrictions(p)
+		if err != nil {
+			return err
+		}
+	}
+	for _, se := range p.SelectExpressions {
+		err := se.validate(t)
+		if err != nil {
+			return err
+		}
+	}
+	err := t.validateOrderBy(p.OrderBy)
+	if err != nil {
+		return err
+	}
+	err = t.validateGroupBy(p.GroupBy)
+	if err != nil {
+		return err
+	}
+	if p.Distinct {
+		return t.validateDistinct(p)
+	}
+	return nil
+}
+
+// validateOrderBy checks that ORDER BY columns are the clustering-columns,
+// in the order they appear in primary-key. The ordering must either
+// match the clustering-order for all columns, or reverse it for all columns.
+func (t *Table) validateOrderBy(orderBy []ColumnOrder) error {
+	clusteringColumns := t.ClusteringColumns()
+	if len(orderBy) > len(clusteringColumns) {
+		return errors.New("ORDER BY can only include clustering-columns")
+	}
+
+	var reversed bool
+	for i, co := range orderBy {
+		order := co.order()
+		if order != "ASC" && order != "DESC" {
+			return fmt.Errorf(
+				"Invalid order \"%s\" for ORDER BY. Errored Key: \"%s\"", co.Order, co.Name,
+			)
+		}
+		column := clusteringColumns[i]
+		if co.Name != column.Name {
+			return fmt.Errorf(
+				"ORDER BY columns must follow the clustering-columns order."+
+					" Expected: \"%s\", Found: \"%s\"",
+				column.Name,
+				co.Name,
+			)
+		}
+
+		isReversed := order != clusteringOrder(column)
+		if i == 0 {
+			reversed = isReversed
+		} else if isReversed != reversed {
+			return fmt.Errorf(
+				"ORDER BY must either match or reverse the clustering-order for all"+
+					" columns. Errored Key: \"%s\"",
+				co.Name,
+			)
+		}
+	}
+	return nil
+}
+
+// validateGroupBy checks that GROUP BY columns are a primary-key prefix,
+// which includes all partition-key columns.
+func (t *Table) validateGroupBy(groupBy []string) error {
+	if len(groupBy) == 0 {
+		return nil
+	}
+	primaryKey := t.PrimaryKeyColumns()
+	if len(groupBy) < len(t.PartitionKeyColumns()) || len(groupBy) > len(primaryKey) {
+		return errors.New(
+			"GROUP BY must include all partition-key columns," +
+				" optionally followed by clustering-columns",
+		)
+	}
+	for i, column := range groupBy {
+		if column != primaryKey[i] {
+			return fmt.Errorf(
+				"GROUP BY columns must follow the primary-key order."+
+					" Expected: \"%s\", Found: \"%s\"",
+				primaryKey[i],
+				column,
+			)
+		}
+	}
+	return nil
+}
+
+// validateDistinct checks that DISTINCT queries only select partition-key
+// and static columns, and include all partition-key columns.
+func (t *Table) validateDistinct(p SelectParams) error {
+	if p.PerPartitionLimit != 0 {
+		return errors.New("PER PARTITION LIMIT cannot be used with DISTINCT")
+	}
+	selectColumns := append([]string{}, p.SelectColumns...)
+	for _, se := range p.SelectExpressions {
+		selectColumns = append(selectColumns, se.columns...)
+	}
+	if len(selectColumns) == 0 {
+		// All partition-key columns are selected by default
+		return nil
+	}
+
+	partitionKey := t.PartitionKeyColumns()
+	selected := make(map[string]bool, len(selectColumns))
+	for _, column := range selectColumns {
+		key := t.definitionKey(column)
+		if key == "" {
+			return fmt.Errorf("No column matching %s was found", column)
+		}
+		def := (*t.Definition())[key]
+		if def.PrimaryKeyIndex != "0" && !def.Static {
+			return fmt.Errorf(
+				"DISTINCT can only select partition-key and static columns."+
+					" Errored Key: \"%s\"",
+				column,
+			)
+		}
+		selected[column] = true
+	}
+	for _, column := range partitionKey {
+		if !selected[column] {
+			return fmt.Errorf(
+				"DISTINCT must select all partition-key columns. Missing Key: \"%s\"",
+				column,
+			)
+		}
+	}
+	return nil
+}
+
+// order returns the normalized order, defaulting to ASC.
+func (co ColumnOrder) order() string {
+	if co.Order == "" {
+		return "ASC"
+	}
+	return strings.ToUpper(co.Order)
+}
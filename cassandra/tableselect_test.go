@@ -227,5 +227,204 @@ var _ = Describe("Table", func() {
 			_, err := table.Select(sp)
 			Expect(err).To(HaveOccurred())
 		})
+
+		Context("ORDER BY, DISTINCT, GROUP BY and other clauses are specified", func() {
+			var query driver.QueryI
+
+			BeforeEach(func() {
+				table.initIterx = func(q driver.QueryI) driver.IterxI {
+					query = q
+					return &mocks.Iterx{
+						CqlQuery: q,
+					}
+				}
+			})
+
+			It("should add ORDER BY clause", func() {
+				sp.OrderBy = []ColumnOrder{Asc("timestamp"), Desc("uuid")}
+				_, err := table.Select(sp)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(query.Statement()).To(Equal(
+					"SELECT month_bucket,timestamp FROM test.test_table" +
+						" WHERE month_bucket=? ORDER BY timestamp ASC,uuid DESC ",
+				))
+			})
+
+			It("should validate ORDER BY against clustering-columns", func() {
+				// Not following clustering-columns order
+				sp.OrderBy = []ColumnOrder{Asc("uuid")}
+				_, err := table.Select(sp)
+				Expect(err).To(HaveOccurred())
+
+				// Not a clustering-column
+				sp.OrderBy = []ColumnOrder{Asc("textcol1")}
+				_, err = table.Select(sp)
+				Expect(err).To(HaveOccurred())
+
+				// Partially reversing clustering-order
+				sp.OrderBy = []ColumnOrder{Desc("timestamp"), Desc("uuid")}
+				_, err = table.Select(sp)
+				Expect(err).To(HaveOccurred())
+
+				sp.OrderBy = []ColumnOrder{ColumnOrder{Name: "timestamp", Order: "up"}}
+				_, err = table.Select(sp)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should select partition-key columns with DISTINCT", func() {
+				sp.Distinct = true
+				sp.SelectColumns = nil
+				sp.ColumnValues = nil
+				_, err := table.Select(sp)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(query.Statement()).To(Equal(
+					"SELECT DISTINCT month_bucket FROM test.test_table ",
+				))
+			})
+
+			It("should only allow partition-key and static columns with DISTINCT", func() {
+				sp.Distinct = true
+				_, err := table.Select(sp)
+				Expect(err).To(HaveOccurred())
+
+				sp.SelectColumns = []string{"month_bucket"}
+				sp.PerPartitionLimit = 1
+				_, err = table.Select(sp)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should add GROUP BY, PER PARTITION LIMIT and ALLOW FILTERING", func() {
+				sp.GroupBy = []string{"month_bucket", "timestamp"}
+				sp.PerPartitionLimit = 2
+				sp.Limit = 10
+				sp.AllowFiltering = true
+				_, err := table.Select(sp)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(query.Statement()).To(Equal(
+					"SELECT month_bucket,timestamp FROM test.test_table" +
+						" WHERE month_bucket=? GROUP BY month_bucket,timestamp" +
+						" PER PARTITION LIMIT 2 LIMIT 10 ALLOW FILTERING ",
+				))
+			})
+
+			It("should validate GROUP BY against primary-key", func() {
+				sp.GroupBy = []string{"timestamp"}
+				_, err := table.Select(sp)
+				Expect(err).To(HaveOccurred())
+
+				sp.GroupBy = []string{"month_bucket", "uuid"}
+				_, err = table.Select(sp)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should return error if comparison-operator is not set", func() {
+				sp.ColumnValues = []ColumnComparator{Comparator("month_bucket", 9)}
+				_, err := table.Select(sp)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("select-expressions are specified", func() {
+			var query driver.QueryI
+
+			BeforeEach(func() {
+				table.initIterx = func(q driver.QueryI) driver.IterxI {
+					query = q
+					return &mocks.Iterx{
+						CqlQuery: q,
+					}
+				}
+			})
+
+			It("should add the expressions after select-columns", func() {
+				sp.SelectExpressions = []SelectExpression{
+					WriteTime("textcol1").As("written_at"),
+					TTL("textcol1").As("ttl"),
+				}
+				_, err := table.Select(sp)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(query.Statement()).To(Equal(
+					"SELECT month_bucket,timestamp,WRITETIME(textcol1) AS written_at," +
+						"TTL(textcol1) AS ttl FROM test.test_table WHERE month_bucket=? ",
+				))
+			})
+
+			It("should return error on unknown columns or invalid aliases", func() {
+				sp.SelectExpressions = []SelectExpression{Max("invalid")}
+				_, err := table.Select(sp)
+				Expect(err).To(HaveOccurred())
+
+				sp.SelectExpressions = []SelectExpression{Max("uuid").As("max-uuid")}
+				_, err = table.Select(sp)
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should count the rows", func() {
+				table.initIterx = func(q driver.QueryI) driver.IterxI {
+					query = q
+					return &mocks.Iterx{
+						CqlQuery: q,
+						MockSelect: func(dest interface{}) error {
+							results := reflect.ValueOf(dest).Elem()
+							row := reflect.New(results.Type().Elem()).Elem()
+							row.Field(0).SetInt(42)
+							results.Set(reflect.Append(results, row))
+							return nil
+						},
+					}
+				}
+
+				sp.OrderBy = []ColumnOrder{Desc("timestamp")}
+				count, err := table.Count(sp)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(count).To(Equal(int64(42)))
+				Expect(query.Statement()).To(Equal(
+					"SELECT COUNT(*) AS count FROM test.test_table WHERE month_bucket=? ",
+				))
+			})
+
+			It("should return any error that occurs when counting", func() {
+				table.initIterx = func(q driver.QueryI) driver.IterxI {
+					return &mocks.Iterx{
+						CqlQuery: q,
+						MockSelect: func(dest interface{}) error {
+							return errors.New("some-error")
+						},
+					}
+				}
+				_, err := table.Count(sp)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		It("should pass the tuple-comparator values as bind-values", func() {
+			var (
+				stmt   string
+				values []interface{}
+			)
+			table.session = &mocks.Session{
+				MockQuery: func(s string, v ...interface{}) {
+					stmt = s
+					values = v
+				},
+			}
+			table.initIterx = func(q driver.QueryI) driver.IterxI {
+				return &mocks.Iterx{
+					CqlQuery: q,
+				}
+			}
+
+			sp.ColumnValues = append(
+				sp.ColumnValues,
+				TupleComparator([]string{"timestamp", "uuid"}, "ts", "id").Lt(),
+			)
+			_, err := table.Select(sp)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stmt).To(Equal(
+				"SELECT month_bucket,timestamp FROM test.test_table" +
+					" WHERE month_bucket=? AND (timestamp,uuid)<(?,?) ",
+			))
+			Expect(values).To(Equal([]interface{}{9, "ts", "id"}))
+		})
 	})
 })
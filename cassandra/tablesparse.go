@@ -0,0 +1,183 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	cql "github.com/gocql/gocql"
+	cqlx "github.com/scylladb/gocqlx"
+	"github.com/scylladb/gocqlx/reflectx"
+)
+
+// OmitPolicy defines which struct-fields are omitted when writing a struct,
+// so the omitted columns are not overwritten with nulls (which creates
+// tombstones). The primary-key and version columns are never omitted.
+type OmitPolicy int
+
+const (
+	// OmitNone writes all fields, except the ones with "omitempty"
+	// option in "db" struct-tag (such as `db:"data,omitempty"`)
+	// which are omitted if zero.
+	OmitNone OmitPolicy = iota
+	// OmitNil omits nil pointer, slice, map and interface fields.
+	OmitNil
+	// OmitZero omits nil and zero-valued fields.
+	OmitZero
+)
+
+// WriteParams defines parameters for #InsertStruct and #UpdateStruct.
+type WriteParams struct {
+	Omit OmitPolicy
+	// Binds the omitted columns as gocql.UnsetValue, instead of removing them
+	// from statement. This keeps the statement same for all writes (so a
+	// single prepared-statement is used), but requires protocol-version 4+.
+	UseUnset bool
+}
+
+// InsertStruct inserts the struct-fields (dataStruct must be a pointer to
+// struct) as a row, except the fields omitted by WriteParams.
+// If table has Bucketing, the bucket-column field is set from time-column
+// field before writing.
+func (t *Table) InsertStruct(dataStruct interface{}, p WriteParams) error {
+	columnValues, err := t.structColumnValues(dataStruct, p)
+	if err != nil {
+		return err
+	}
+
+	stmt, values := t.insertStatement(columnValues)
+	return t.Session().Query(stmt, values...).Exec()
+}
+
+// insertStatement returns the INSERT statement and its bind-values for
+// columnValues, with column-names as keys.
+func (t *Table) insertStatement(
+	columnValues map[string]interface{},
+) (string, []interface{}) {
+	columns := []string{}
+	values := []interface{}{}
+	for _, column := range t.Columns() {
+		value, isSet := columnValues[column]
+		if !isSet {
+			continue
+		}
+		columns = append(columns, column)
+		values = append(values, value)
+	}
+
+	stmt := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s) ",
+		t.FullName(),
+		strings.Join(quoteIdentifiers(columns), ","),
+		placeholders(len(columns)),
+	)
+	return stmt, values
+}
+
+// UpdateStruct updates the row identified by primary-key fields of
+// dataStruct (pointer to struct), setting the other fields except the ones
+// omitted by WriteParams. See #Update.
+// If table has VersionColumn, the version-field is the expected version,
+// and is incremented after the update.
+func (t *Table) UpdateStruct(
+	ctx context.Context,
+	dataStruct interface{},
+	p WriteParams,
+) error {
+	columnValues, err := t.structColumnValues(dataStruct, p)
+	if err != nil {
+		return err
+	}
+
+	primaryKey := t.PrimaryKeyColumns()
+	ccs := make([]ColumnComparator, len(primaryKey))
+	for i, column := range primaryKey {
+		ccs[i] = Comparator(column, columnValues[column]).Eq()
+		delete(columnValues, column)
+	}
+	if t.versionColumn == "" {
+		return t.Update(ctx, UpdateParams{
+			ColumnValues: ccs,
+			Values:       columnValues,
+		})
+	}
+
+	version := columnValues[t.versionColumn]
+	delete(columnValues, t.versionColumn)
+	err = t.Update(ctx, UpdateParams{
+		ColumnValues: ccs,
+		Values:       columnValues,
+		Version:      version,
+	})
+	if err != nil {
+		return err
+	}
+	// Version was validated by #Update
+	next, _ := nextVersion(version)
+	field := cqlx.DefaultMapper.FieldByName(reflect.ValueOf(dataStruct), t.versionColumn)
+	field.Set(reflect.ValueOf(next))
+	return nil
+}
+
+// structColumnValues returns the struct-field values, with column-names
+// as keys. The omitted columns are not included, or are set to
+// gocql.UnsetValue if WriteParams.UseUnset is true.
+func (t *Table) structColumnValues(
+	dataStruct interface{},
+	p WriteParams,
+) (map[string]interface{}, error) {
+	v := reflect.ValueOf(dataStruct)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("Data must be a pointer to struct")
+	}
+	if t.bucketing != nil {
+		err := t.bucketing.setBucket(dataStruct)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	primaryKey := t.PrimaryKeyColumns()
+	fields := cqlx.DefaultMapper.TypeMap(v.Type()).Names
+	columnValues := make(map[string]interface{})
+	for _, column := range t.Columns() {
+		fi, exists := fields[column]
+		if !exists {
+			return nil, fmt.Errorf("No struct-field found for column %s", column)
+		}
+		field := reflectx.FieldByIndexesReadOnly(v, fi.Index)
+
+		_, omitEmpty := fi.Options["omitempty"]
+		omitNil := p.Omit != OmitNone && isNil(field)
+		omitZero := (p.Omit == OmitZero || omitEmpty) && isZero(field)
+		omit := (omitNil || omitZero) &&
+			!isPrimaryKey(primaryKey, column) && column != t.versionColumn
+		if !omit {
+			columnValues[column] = field.Interface()
+		} else if p.UseUnset {
+			columnValues[column] = cql.UnsetValue
+		}
+	}
+	return columnValues, nil
+}
+
+// isNil checks if value is a nil pointer, slice, map or interface.
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// isZero checks if value is the zero-value of its type. Empty slices
+// and maps are considered zero, since these are stored as nulls.
+func isZero(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
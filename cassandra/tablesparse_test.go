@@ -0,0 +1,136 @@
+package cassandra
+
+import (
+	"context"
+
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	"github.com/TerrexTech/go-commonutils/utils"
+	cql "github.com/gocql/gocql"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Table", func() {
+	Context("structs are written sparsely", func() {
+		type item struct {
+			ID    int      `db:"id"`
+			Data  string   `db:"data"`
+			Count *int     `db:"count"`
+			Tags  []string `db:"tags"`
+			Note  string   `db:"note,omitempty"`
+		}
+
+		var (
+			stmt   string
+			values []interface{}
+			table  *Table
+		)
+
+		BeforeEach(func() {
+			session := &mocks.Session{
+				MockQuery: func(s string, v ...interface{}) {
+					stmt = utils.StandardizeSpaces(s)
+					values = v
+				},
+			}
+			keyspace, err := NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			table, err = NewTable(session, &TableConfig{
+				Keyspace: keyspace,
+				Name:     "test_table",
+			}, &map[string]TableColumn{
+				"id": TableColumn{
+					Name:            "id",
+					DataType:        "int",
+					PrimaryKeyIndex: "0",
+				},
+				"data": TableColumn{
+					Name:     "data",
+					DataType: "text",
+				},
+				"count": TableColumn{
+					Name:     "count",
+					DataType: "int",
+				},
+				"tags": TableColumn{
+					Name:     "tags",
+					DataType: "list<text>",
+				},
+				"note": TableColumn{
+					Name:     "note",
+					DataType: "text",
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should only omit omitempty fields by default", func() {
+			err := table.InsertStruct(&item{ID: 0}, WriteParams{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stmt).To(Equal(
+				"INSERT INTO test.test_table (count,data,id,tags) VALUES (?,?,?,?)",
+			))
+			Expect(values).To(HaveLen(4))
+		})
+
+		It("should omit nil fields", func() {
+			err := table.InsertStruct(&item{ID: 1}, WriteParams{Omit: OmitNil})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stmt).To(Equal("INSERT INTO test.test_table (data,id) VALUES (?,?)"))
+			Expect(values).To(Equal([]interface{}{"", 1}))
+		})
+
+		It("should omit zero fields, but not primary-key", func() {
+			count := 0
+			err := table.InsertStruct(&item{
+				ID:    0,
+				Count: &count,
+				Tags:  []string{},
+			}, WriteParams{Omit: OmitZero})
+			Expect(err).ToNot(HaveOccurred())
+			// Non-nil pointers are not zero
+			Expect(stmt).To(Equal("INSERT INTO test.test_table (count,id) VALUES (?,?)"))
+			Expect(values).To(Equal([]interface{}{&count, 0}))
+		})
+
+		It("should bind omitted columns as unset", func() {
+			err := table.InsertStruct(&item{ID: 1, Note: "n"}, WriteParams{
+				Omit:     OmitZero,
+				UseUnset: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stmt).To(Equal(
+				"INSERT INTO test.test_table (count,data,id,note,tags) VALUES (?,?,?,?,?)",
+			))
+			Expect(values).To(Equal([]interface{}{
+				cql.UnsetValue, cql.UnsetValue, 1, "n", cql.UnsetValue,
+			}))
+		})
+
+		It("should update non-omitted fields by primary-key", func() {
+			ctx := context.Background()
+			err := table.UpdateStruct(ctx, &item{ID: 1, Data: "d"}, WriteParams{Omit: OmitZero})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stmt).To(Equal("UPDATE test.test_table SET data=? WHERE id=?"))
+			Expect(values).To(Equal([]interface{}{"d", 1}))
+
+			err = table.UpdateStruct(ctx, &item{ID: 1}, WriteParams{Omit: OmitZero})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should return error if data is not a struct-pointer or misses fields", func() {
+			err := table.InsertStruct(item{}, WriteParams{})
+			Expect(err).To(HaveOccurred())
+
+			type partial struct {
+				ID int `db:"id"`
+			}
+			err = table.InsertStruct(&partial{}, WriteParams{})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,144 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UpdateParams defines parameters for an UPDATE query.
+type UpdateParams struct {
+	// Restrictions for WHERE clause. All primary-key columns must
+	// be restricted by EQ or IN.
+	ColumnValues []ColumnComparator
+	// Values to SET, with column-names (as in database) as keys.
+	// Primary-key columns cannot be updated.
+	Values map[string]interface{}
+	// Skips validating ColumnValues restrictions against primary-key.
+	SkipRestrictionValidation bool
+	// Expected current row-version, required if table has VersionColumn.
+	// The row is only updated if its version matches, and the version
+	// is incremented.
+	Version interface{}
+}
+
+// DeleteParams defines parameters for a DELETE query.
+type DeleteParams struct {
+	// Restrictions for WHERE clause. All partition-key columns must be
+	// restricted by EQ or IN. When deleting complete rows, a clustering-columns
+	// prefix can be restricted (with a range-restriction on last restricted
+	// column), otherwise all clustering-columns must be restricted by EQ or IN.
+	ColumnValues []ColumnComparator
+	// Columns to delete. The complete rows are deleted if not specified.
+	Columns []string
+	// Skips validating ColumnValues restrictions against primary-key.
+	SkipRestrictionValidation bool
+}
+
+// Update updates the rows matching the WHERE clause with provided values.
+// If table has VersionColumn, a single row is updated using a
+// lightweight-transaction, and ErrConcurrentModification is returned
+// if its version doesn't match UpdateParams.Version.
+func (t *Table) Update(ctx context.Context, p UpdateParams) error {
+	if len(p.Values) == 0 {
+		return errors.New("No values specified to update")
+	}
+	if !p.SkipRestrictionValidation {
+		err := t.validateWriteRestrictions("UPDATE", p.ColumnValues, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	columns := make([]string, 0, len(p.Values))
+	for column := range p.Values {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	err := t.validateNonKeyColumns("UPDATE", columns)
+	if err != nil {
+		return err
+	}
+	err = t.validateVersionNotSet(p.Values)
+	if err != nil {
+		return err
+	}
+
+	assignments := make([]string, len(columns))
+	values := make([]interface{}, len(columns))
+	for i, column := range columns {
+		assignments[i] = fmt.Sprintf("%s=?", QuoteIdentifier(column))
+		values[i] = p.Values[column]
+	}
+
+	where, whereValues, err := whereClause(p.ColumnValues)
+	if err != nil {
+		return err
+	}
+	if t.versionColumn != "" {
+		applied, current, err := t.updateVersioned(
+			ctx, assignments, values, where, whereValues, nil, p.Version,
+		)
+		if err != nil {
+			return err
+		}
+		if !applied {
+			return &ErrConcurrentModification{
+				Expected: p.Version,
+				Current:  current[t.versionColumn],
+			}
+		}
+		return nil
+	}
+	stmt := fmt.Sprintf(
+		"UPDATE %s SET %s %s", t.FullName(), strings.Join(assignments, ","), where,
+	)
+	return t.Session().
+		Query(stmt, append(values, whereValues...)...).
+		WithContext(ctx).
+		Exec()
+}
+
+// Delete deletes the rows (or the specified columns of rows)
+// matching the WHERE clause.
+func (t *Table) Delete(p DeleteParams) error {
+	if !p.SkipRestrictionValidation {
+		err := t.validateWriteRestrictions("DELETE", p.ColumnValues, len(p.Columns) > 0)
+		if err != nil {
+			return err
+		}
+	}
+	err := t.validateNonKeyColumns("DELETE", p.Columns)
+	if err != nil {
+		return err
+	}
+
+	where, values, err := whereClause(p.ColumnValues)
+	if err != nil {
+		return err
+	}
+	stmt := "DELETE "
+	if len(p.Columns) > 0 {
+		stmt += strings.Join(quoteIdentifiers(p.Columns), ",") + " "
+	}
+	stmt += fmt.Sprintf("FROM %s %s", t.FullName(), where)
+	return t.Session().Query(stmt, values...).Exec()
+}
+
+// validateNonKeyColumns checks that columns exist, and are not primary-key columns.
+func (t *Table) validateNonKeyColumns(stmt string, columns []string) error {
+	for _, column := range columns {
+		key := t.definitionKey(column)
+		if key == "" {
+			return fmt.Errorf("No column matching %s was found", column)
+		}
+		if (*t.Definition())[key].PrimaryKeyIndex != "" {
+			return fmt.Errorf(
+				"%s cannot modify primary-key columns. Errored Key: \"%s\"", stmt, column,
+			)
+		}
+	}
+	return nil
+}
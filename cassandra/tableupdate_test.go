@@ -0,0 +1,109 @@
+package cassandra
+
+import (
+	"context"
+
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	"github.com/TerrexTech/go-commonutils/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Table", func() {
+	Context("data is updated or deleted", func() {
+		var (
+			stmt    string
+			values  []interface{}
+			session *mocks.Session
+			table   *Table
+		)
+
+		BeforeEach(func() {
+			session = &mocks.Session{
+				MockQuery: func(s string, v ...interface{}) {
+					stmt = utils.StandardizeSpaces(s)
+					values = v
+				},
+			}
+			keyspace, err := NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			table, err = NewTable(session, &TableConfig{
+				Keyspace: keyspace,
+				Name:     "test_table",
+			}, &map[string]TableColumn{
+				"id": TableColumn{
+					Name:            "id",
+					DataType:        "uuid",
+					PrimaryKeyIndex: "0",
+				},
+				"data": TableColumn{
+					Name:     "data",
+					DataType: "text",
+				},
+				"order": TableColumn{
+					Name:     "order",
+					DataType: "int",
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should update the specified values", func() {
+			err := table.Update(context.Background(), UpdateParams{
+				ColumnValues: []ColumnComparator{Comparator("id", "id1").Eq()},
+				Values: map[string]interface{}{
+					"order": 2,
+					"data":  "d",
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stmt).To(Equal(`UPDATE test.test_table SET data=?,"order"=? WHERE id=?`))
+			Expect(values).To(Equal([]interface{}{"d", 2, "id1"}))
+		})
+
+		It("should return error when updating primary-key or unknown columns", func() {
+			ccs := []ColumnComparator{Comparator("id", "id1").Eq()}
+			err := table.Update(context.Background(), UpdateParams{
+				ColumnValues: ccs,
+				Values:       map[string]interface{}{"id": "id2"},
+			})
+			Expect(err).To(HaveOccurred())
+
+			err = table.Update(context.Background(), UpdateParams{
+				ColumnValues: ccs,
+				Values:       map[string]interface{}{"invalid": 1},
+			})
+			Expect(err).To(HaveOccurred())
+
+			err = table.Update(context.Background(), UpdateParams{ColumnValues: ccs})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should delete rows or columns", func() {
+			ccs := []ColumnComparator{Comparator("id", "id1").Eq()}
+			err := table.Delete(DeleteParams{ColumnValues: ccs})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stmt).To(Equal("DELETE FROM test.test_table WHERE id=?"))
+			Expect(values).To(Equal([]interface{}{"id1"}))
+
+			err = table.Delete(DeleteParams{
+				ColumnValues: ccs,
+				Columns:      []string{"data", "order"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stmt).To(Equal(`DELETE data,"order" FROM test.test_table WHERE id=?`))
+		})
+
+		It("should return any error that occurs when executing query", func() {
+			session.MockQueryExecError = "some-error"
+			err := table.Delete(DeleteParams{
+				ColumnValues: []ColumnComparator{Comparator("id", "id1").Eq()},
+			})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,156 @@
+package cassandra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrConcurrentModification is returned by versioned updates (see
+// TableConfig.VersionColumn) if the row-version doesn't match the
+// expected version, since the row was modified concurrently.
+type ErrConcurrentModification struct {
+	// Expected row-version
+	Expected interface{}
+	// Current row-version, nil if the row doesn't exist
+	Current interface{}
+}
+
+func (e *ErrConcurrentModification) Error() string {
+	if e.Current == nil {
+		return fmt.Sprintf(
+			"Concurrent modification: expected version %v, but row doesn't exist",
+			e.Expected,
+		)
+	}
+	return fmt.Sprintf(
+		"Concurrent modification: expected version %v, found %v", e.Expected, e.Current,
+	)
+}
+
+// VersionColumn returns the column storing row-version, as set in TableConfig.
+func (t *Table) VersionColumn() string {
+	return t.versionColumn
+}
+
+// updateVersioned executes the UPDATE using a lightweight-transaction,
+// conditioned on row-version being expected (and on conditions, if any),
+// and increments the version. Returns if it was applied, and the current
+// values of condition-columns if it wasn't.
+func (t *Table) updateVersioned(
+	ctx context.Context,
+	assignments []string,
+	values []interface{},
+	where string,
+	whereValues []interface{},
+	conditions []ColumnComparator,
+	expected interface{},
+) (bool, map[string]interface{}, error) {
+	next, err := nextVersion(expected)
+	if err != nil {
+		return false, nil, err
+	}
+	assignments = append(assignments, fmt.Sprintf("%s=?", QuoteIdentifier(t.versionColumn)))
+	values = append(values, next)
+
+	conditions = append(
+		append([]ColumnComparator{}, conditions...),
+		Comparator(t.versionColumn, expected).Eq(),
+	)
+	condition, conditionValues, err := t.ifClause(conditions)
+	if err != nil {
+		return false, nil, err
+	}
+	stmt := fmt.Sprintf(
+		"UPDATE %s SET %s %s%s",
+		t.FullName(),
+		strings.Join(assignments, ","),
+		where,
+		condition,
+	)
+	values = append(values, whereValues...)
+	return t.execCAS(ctx, stmt, append(values, conditionValues...))
+}
+
+// versionCondition separates the equality-condition on version-column
+// (the expected version) from other conditions, for #CompareAndSet.
+func (t *Table) versionCondition(
+	conditions []ColumnComparator,
+) ([]ColumnComparator, interface{}, error) {
+	var expected interface{}
+	others := []ColumnComparator{}
+	for _, cc := range conditions {
+		if cc.Name != t.versionColumn || cc.columns != nil {
+			others = append(others, cc)
+			continue
+		}
+		if cc.operator != "=" || expected != nil {
+			return nil, nil, fmt.Errorf(
+				"Version column must have a single equality condition. Errored Key: \"%s\"",
+				t.versionColumn,
+			)
+		}
+		expected = cc.Value
+	}
+	if expected == nil {
+		return nil, nil, fmt.Errorf(
+			"CompareAndSet on versioned table requires the expected version as"+
+				" condition on version column. Errored Key: \"%s\"",
+			t.versionColumn,
+		)
+	}
+	return others, expected, nil
+}
+
+// validateVersionNotSet checks that values (with column-names as keys)
+// don't set the version-column, which is only updated by versioned updates.
+func (t *Table) validateVersionNotSet(values map[string]interface{}) error {
+	if _, exists := values[t.versionColumn]; exists && t.versionColumn != "" {
+		return fmt.Errorf(
+			"Version column is only updated by versioned updates. Errored Key: \"%s\"",
+			t.versionColumn,
+		)
+	}
+	return nil
+}
+
+// validateVersionColumn checks that version-column (if set) is a
+// non-primary-key integer column.
+func (t *Table) validateVersionColumn() error {
+	if t.versionColumn == "" {
+		return nil
+	}
+	err := t.validateNonKeyColumns("VersionColumn", []string{t.versionColumn})
+	if err != nil {
+		return err
+	}
+	key := t.definitionKey(t.versionColumn)
+	switch strings.ToLower((*t.Definition())[key].DataType) {
+	case "tinyint", "smallint", "int", "bigint", "varint":
+		return nil
+	}
+	return fmt.Errorf(
+		"VersionColumn must have an integer data-type. Errored Key: \"%s\"",
+		t.versionColumn,
+	)
+}
+
+// nextVersion returns the version following version, of same type.
+func nextVersion(version interface{}) (interface{}, error) {
+	v := reflect.ValueOf(version)
+	if !v.IsValid() {
+		return nil, errors.New("Versioned update requires the expected Version")
+	}
+	next := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		next.SetInt(v.Int() + 1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		next.SetUint(v.Uint() + 1)
+	default:
+		return nil, fmt.Errorf("Version must be an integer, found: %v", version)
+	}
+	return next.Interface(), nil
+}
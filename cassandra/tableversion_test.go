@@ -0,0 +1,237 @@
+package cassandra
+
+import (
+	"context"
+
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	"github.com/TerrexTech/go-commonutils/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Table", func() {
+	Context("table has version column", func() {
+		type aggregate struct {
+			ID      string `db:"id"`
+			State   string `db:"state"`
+			Version int64  `db:"version"`
+		}
+
+		var (
+			stmt       string
+			values     []interface{}
+			session    *mocks.Session
+			keyspace   *Keyspace
+			definition map[string]TableColumn
+		)
+
+		newTable := func(versionColumn string) (*Table, error) {
+			return NewTable(session, &TableConfig{
+				Keyspace:      keyspace,
+				Name:          "test_table",
+				VersionColumn: versionColumn,
+			}, &definition)
+		}
+
+		BeforeEach(func() {
+			session = &mocks.Session{
+				MockQuery: func(s string, v ...interface{}) {
+					stmt = utils.StandardizeSpaces(s)
+					values = v
+				},
+			}
+			var err error
+			keyspace, err = NewKeyspace(session, KeyspaceConfig{
+				Name:        "test",
+				Replication: SimpleStrategy{ReplicationFactor: 1},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			definition = map[string]TableColumn{
+				"id": TableColumn{
+					Name:            "id",
+					DataType:        "text",
+					PrimaryKeyIndex: "0",
+				},
+				"state": TableColumn{
+					Name:     "state",
+					DataType: "text",
+				},
+				"version": TableColumn{
+					Name:     "version",
+					DataType: "bigint",
+				},
+			}
+		})
+
+		It("should validate the version column", func() {
+			for _, column := range []string{"invalid", "id", "state"} {
+				_, err := newTable(column)
+				Expect(err).To(HaveOccurred())
+			}
+			table, err := newTable("version")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(table.VersionColumn()).To(Equal("version"))
+		})
+
+		It("should check and increment version on update", func() {
+			table, err := newTable("version")
+			Expect(err).ToNot(HaveOccurred())
+
+			err = table.Update(context.Background(), UpdateParams{
+				ColumnValues: []ColumnComparator{Comparator("id", "a1").Eq()},
+				Values:       map[string]interface{}{"state": "s2"},
+				Version:      int64(3),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stmt).To(Equal(
+				"UPDATE test.test_table SET state=?,version=? WHERE id=? IF version=?",
+			))
+			Expect(values).To(Equal([]interface{}{"s2", int64(4), "a1", int64(3)}))
+		})
+
+		It("should return error if version is missing or set in values", func() {
+			table, err := newTable("version")
+			Expect(err).ToNot(HaveOccurred())
+
+			invalid := []UpdateParams{
+				UpdateParams{
+					ColumnValues: []ColumnComparator{Comparator("id", "a1").Eq()},
+					Values:       map[string]interface{}{"state": "s2"},
+				},
+				UpdateParams{
+					ColumnValues: []ColumnComparator{Comparator("id", "a1").Eq()},
+					Values:       map[string]interface{}{"state": "s2"},
+					Version:      "3",
+				},
+				UpdateParams{
+					ColumnValues: []ColumnComparator{Comparator("id", "a1").Eq()},
+					Values:       map[string]interface{}{"version": int64(5)},
+					Version:      int64(3),
+				},
+			}
+			for _, p := range invalid {
+				Expect(table.Update(context.Background(), p)).To(HaveOccurred())
+			}
+		})
+
+		It("should return ErrConcurrentModification if not applied", func() {
+			table, err := newTable("version")
+			Expect(err).ToNot(HaveOccurred())
+
+			session.MockQueryMapScanCAS = func(dest map[string]interface{}) (bool, error) {
+				dest["version"] = int64(5)
+				return false, nil
+			}
+			err = table.Update(context.Background(), UpdateParams{
+				ColumnValues: []ColumnComparator{Comparator("id", "a1").Eq()},
+				Values:       map[string]interface{}{"state": "s2"},
+				Version:      int64(3),
+			})
+			Expect(err).To(Equal(&ErrConcurrentModification{
+				Expected: int64(3),
+				Current:  int64(5),
+			}))
+			Expect(err.Error()).To(ContainSubstring("found 5"))
+		})
+
+		It("should pass the context to versioned update", func() {
+			table, err := newTable("version")
+			Expect(err).ToNot(HaveOccurred())
+
+			var queryCtx context.Context
+			session.MockQueryWithContext = func(ctx context.Context) {
+				queryCtx = ctx
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			err = table.Update(ctx, UpdateParams{
+				ColumnValues: []ColumnComparator{Comparator("id", "a1").Eq()},
+				Values:       map[string]interface{}{"state": "s2"},
+				Version:      int64(3),
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(queryCtx).To(Equal(ctx))
+		})
+
+		It("should check and increment version on CompareAndSet", func() {
+			table, err := newTable("version")
+			Expect(err).ToNot(HaveOccurred())
+
+			applied, _, err := table.CompareAndSet(
+				context.Background(),
+				map[string]interface{}{"id": "a1"},
+				[]ColumnComparator{
+					Comparator("state", "s1").Eq(),
+					Comparator("version", int64(3)).Eq(),
+				},
+				map[string]interface{}{"state": "s2"},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(applied).To(BeTrue())
+			Expect(stmt).To(Equal(
+				"UPDATE test.test_table SET state=?,version=? WHERE id=? IF state=? AND version=?",
+			))
+			Expect(values).To(Equal([]interface{}{"s2", int64(4), "a1", "s1", int64(3)}))
+		})
+
+		It("should require the expected version on CompareAndSet", func() {
+			table, err := newTable("version")
+			Expect(err).ToNot(HaveOccurred())
+			stmt = ""
+
+			invalid := [][]ColumnComparator{
+				nil,
+				[]ColumnComparator{Comparator("state", "s1").Eq()},
+				[]ColumnComparator{Comparator("version", int64(3)).Gt()},
+			}
+			for _, conditions := range invalid {
+				_, _, err = table.CompareAndSet(
+					context.Background(),
+					map[string]interface{}{"id": "a1"},
+					conditions,
+					map[string]interface{}{"state": "s2"},
+				)
+				Expect(err).To(HaveOccurred())
+			}
+			Expect(stmt).To(BeEmpty())
+		})
+
+		It("should return error if CompareAndSet sets the version", func() {
+			table, err := newTable("version")
+			Expect(err).ToNot(HaveOccurred())
+			stmt = ""
+
+			_, _, err = table.CompareAndSet(
+				context.Background(),
+				map[string]interface{}{"id": "a1"},
+				[]ColumnComparator{Comparator("version", int64(3)).Eq()},
+				map[string]interface{}{"state": "s2", "version": int64(10)},
+			)
+			Expect(err).To(HaveOccurred())
+			Expect(stmt).To(BeEmpty())
+		})
+
+		It("should update struct and increment its version", func() {
+			table, err := newTable("version")
+			Expect(err).ToNot(HaveOccurred())
+
+			a := &aggregate{ID: "a1", State: "s2"}
+			err = table.UpdateStruct(context.Background(), a, WriteParams{Omit: OmitZero})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stmt).To(Equal(
+				"UPDATE test.test_table SET state=?,version=? WHERE id=? IF version=?",
+			))
+			Expect(values).To(Equal([]interface{}{"s2", int64(1), "a1", int64(0)}))
+			Expect(a.Version).To(Equal(int64(1)))
+
+			session.MockQueryMapScanCAS = func(dest map[string]interface{}) (bool, error) {
+				return false, nil
+			}
+			err = table.UpdateStruct(context.Background(), a, WriteParams{})
+			_, isConcurrent := err.(*ErrConcurrentModification)
+			Expect(isConcurrent).To(BeTrue())
+			Expect(a.Version).To(Equal(int64(1)))
+		})
+	})
+})
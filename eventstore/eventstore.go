@@ -0,0 +1,438 @@
+// Package eventstore stores the domain-events of aggregates as append-only
+// event-streams, with optional snapshots of aggregate-state.
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	cs "github.com/TerrexTech/go-cassandrautils/cassandra"
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+	cql "github.com/gocql/gocql"
+)
+
+// ErrNoSnapshot is returned by #LatestSnapshot if the aggregate has no snapshots.
+var ErrNoSnapshot = errors.New("No snapshot found for aggregate")
+
+// eventColumns are the columns of events-table, in the order of bind-values
+// for inserting events.
+var eventColumns = []string{
+	"aggregate_id", "bucket", "version", "event_type", "data", "timestamp",
+}
+
+// Config defines configuration for EventStore.
+type Config struct {
+	Keyspace *cs.Keyspace
+	// Name of events-table, defaults to "events"
+	EventsTable string
+	// Name of snapshots-table, defaults to "snapshots"
+	SnapshotsTable string
+	// Number of events per partition of an event-stream, defaults to 1000.
+	// This must not be changed once events are stored.
+	BucketSize int64
+	// Consistency for serial-phase of appends, defaults to gocql.Serial
+	SerialConsistency cql.SerialConsistency
+}
+
+// Event is a domain-event of an aggregate. The version is the position
+// of event in aggregate's event-stream, starting at 1.
+type Event struct {
+	AggregateID string    `db:"aggregate_id"`
+	Version     int64     `db:"version"`
+	EventType   string    `db:"event_type"`
+	Data        []byte    `db:"data"`
+	Timestamp   time.Time `db:"timestamp"`
+}
+
+// Snapshot is the aggregate-state as of version, so the aggregate can be
+// loaded without replaying the complete event-stream.
+type Snapshot struct {
+	AggregateID string    `db:"aggregate_id"`
+	Version     int64     `db:"version"`
+	Data        []byte    `db:"data"`
+	Timestamp   time.Time `db:"timestamp"`
+}
+
+// EventStore appends and reads the event-streams of aggregates.
+// The events of an aggregate are partitioned by aggregate-ID and a bucket
+// (of BucketSize versions), and ordered by version within partitions.
+// Each partition has a static head-version (the version of its last
+// event), on which the appends are conditioned.
+type EventStore struct {
+	bucketSize int64
+	events     *cs.Table
+	// This facilitates mocking by allowing overwriting this
+	iterate func(
+		table *cs.Table,
+		ctx context.Context,
+		p cs.SelectParams,
+		fn func(row interface{}) error,
+	) error
+	serialConsistency cql.SerialConsistency
+	snapshots         *cs.Table
+}
+
+// New creates the events and snapshots tables (if they don't exist), and
+// returns an EventStore using them.
+func New(session driver.SessionI, c Config) (*EventStore, error) {
+	if c.EventsTable == "" {
+		c.EventsTable = "events"
+	}
+	if c.SnapshotsTable == "" {
+		c.SnapshotsTable = "snapshots"
+	}
+	if c.BucketSize == 0 {
+		c.BucketSize = 1000
+	}
+	if c.BucketSize < 0 {
+		return nil, errors.New("BucketSize cannot be negative")
+	}
+	if c.SerialConsistency == 0 {
+		c.SerialConsistency = cql.Serial
+	}
+
+	events, err := cs.NewTable(session, &cs.TableConfig{
+		Keyspace: c.Keyspace,
+		Name:     c.EventsTable,
+	}, &map[string]cs.TableColumn{
+		"aggregateID": cs.TableColumn{
+			Name:              "aggregate_id",
+			DataType:          "text",
+			PrimaryKeyIndex:   "0",
+			PartitionKeyIndex: "0",
+		},
+		"bucket": cs.TableColumn{
+			Name:              "bucket",
+			DataType:          "bigint",
+			PrimaryKeyIndex:   "0",
+			PartitionKeyIndex: "1",
+		},
+		"version": cs.TableColumn{
+			Name:            "version",
+			DataType:        "bigint",
+			PrimaryKeyIndex: "1",
+			PrimaryKeyOrder: "ASC",
+		},
+		"eventType": cs.TableColumn{
+			Name:     "event_type",
+			DataType: "text",
+		},
+		"data": cs.TableColumn{
+			Name:     "data",
+			DataType: "blob",
+		},
+		"timestamp": cs.TableColumn{
+			Name:     "timestamp",
+			DataType: "timestamp",
+		},
+		// Version of the bucket's last event
+		"headVersion": cs.TableColumn{
+			Name:     "head_version",
+			DataType: "bigint",
+			Static:   true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := cs.NewTable(session, &cs.TableConfig{
+		Keyspace: c.Keyspace,
+		Name:     c.SnapshotsTable,
+	}, &map[string]cs.TableColumn{
+		"aggregateID": cs.TableColumn{
+			Name:            "aggregate_id",
+			DataType:        "text",
+			PrimaryKeyIndex: "0",
+		},
+		"version": cs.TableColumn{
+			Name:            "version",
+			DataType:        "bigint",
+			PrimaryKeyIndex: "1",
+			PrimaryKeyOrder: "DESC",
+		},
+		"data": cs.TableColumn{
+			Name:     "data",
+			DataType: "blob",
+		},
+		"timestamp": cs.TableColumn{
+			Name:     "timestamp",
+			DataType: "timestamp",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventStore{
+		bucketSize:        c.BucketSize,
+		events:            events,
+		iterate:           (*cs.Table).Iterate,
+		serialConsistency: c.SerialConsistency,
+		snapshots:         snapshots,
+	}, nil
+}
+
+// EventsTable returns the table storing events.
+func (s *EventStore) EventsTable() *cs.Table {
+	return s.events
+}
+
+// SnapshotsTable returns the table storing snapshots.
+func (s *EventStore) SnapshotsTable() *cs.Table {
+	return s.snapshots
+}
+
+// Append appends the events to aggregate's event-stream, if the stream's
+// current version is expectedVersion (0 for new streams). The events are
+// assigned the following versions, and are returned with AggregateID,
+// Version and Timestamp (if not set) populated.
+// The events are appended atomically using a conditional batch on the
+// partition-bucket's head-version, so the events must all be in the same
+// bucket (append at most BucketSize - expectedVersion % BucketSize events).
+// Returns cassandra.ErrConcurrentModification if the stream isn't at
+// expectedVersion, where Current is the head-version of the bucket (nil
+// if the bucket has no events).
+func (s *EventStore) Append(
+	ctx context.Context,
+	aggregateID string,
+	expectedVersion int64,
+	events []Event,
+) ([]Event, error) {
+	if len(events) == 0 {
+		return nil, errors.New("No events specified to append")
+	}
+	if expectedVersion < 0 {
+		return nil, errors.New("Expected version cannot be negative")
+	}
+
+	appended := make([]Event, len(events))
+	now := time.Now()
+	for i, event := range events {
+		event.AggregateID = aggregateID
+		event.Version = expectedVersion + int64(i) + 1
+		if event.Timestamp.IsZero() {
+			event.Timestamp = now
+		}
+		appended[i] = event
+	}
+
+	bucket := s.bucket(appended[0].Version)
+	if s.bucket(appended[len(appended)-1].Version) != bucket {
+		return nil, fmt.Errorf(
+			"Events must be in a single bucket, at most %d events can be appended"+
+				" at version %d",
+			(bucket+1)*s.bucketSize-expectedVersion,
+			expectedVersion,
+		)
+	}
+
+	// A new bucket continues the previous bucket, which can't change
+	// once its head-version is the bucket's last version
+	var expectedHead interface{}
+	if expectedVersion > 0 && s.bucket(expectedVersion) == bucket {
+		expectedHead = expectedVersion
+	} else if expectedVersion > 0 {
+		head, err := s.headVersion(ctx, aggregateID, bucket-1)
+		if err != nil {
+			return nil, err
+		}
+		if head != expectedVersion {
+			return nil, &cs.ErrConcurrentModification{
+				Expected: expectedVersion,
+				Current:  head,
+			}
+		}
+	}
+
+	err := s.appendBucket(ctx, bucket, expectedHead, appended)
+	if err != nil {
+		return nil, err
+	}
+	return appended, nil
+}
+
+// appendBucket inserts the events (all in same bucket) and sets the
+// bucket's head-version, in a batch conditioned on the current head-version
+// being expectedHead (nil if bucket must not have events).
+func (s *EventStore) appendBucket(
+	ctx context.Context,
+	bucket int64,
+	expectedHead interface{},
+	events []Event,
+) error {
+	aggregateID := events[0].AggregateID
+	stmts := []string{}
+	values := []interface{}{
+		events[len(events)-1].Version, aggregateID, bucket,
+	}
+	condition := "IF head_version = null"
+	if expectedHead != nil {
+		condition = "IF head_version = ?"
+		values = append(values, expectedHead)
+	}
+	stmts = append(stmts, fmt.Sprintf(
+		"UPDATE %s SET head_version = ? WHERE aggregate_id = ? AND bucket = ? %s",
+		s.events.FullName(),
+		condition,
+	))
+
+	quoted := make([]string, len(eventColumns))
+	for i, column := range eventColumns {
+		quoted[i] = cs.QuoteIdentifier(column)
+	}
+	insert := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (?%s)",
+		s.events.FullName(),
+		strings.Join(quoted, ","),
+		strings.Repeat(",?", len(eventColumns)-1),
+	)
+	for _, event := range events {
+		stmts = append(stmts, insert)
+		values = append(
+			values,
+			aggregateID,
+			bucket,
+			event.Version,
+			event.EventType,
+			event.Data,
+			event.Timestamp,
+		)
+	}
+	stmt := fmt.Sprintf("BEGIN BATCH %s; APPLY BATCH ", strings.Join(stmts, "; "))
+
+	current := make(map[string]interface{})
+	applied, err := s.events.Session().
+		Query(stmt, values...).
+		WithContext(ctx).
+		SerialConsistency(s.serialConsistency).
+		MapScanCAS(current)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return &cs.ErrConcurrentModification{
+			Expected: events[0].Version - 1,
+			Current:  versionOrNil(current["head_version"]),
+		}
+	}
+	return nil
+}
+
+// headVersion returns the head-version of aggregate's bucket,
+// nil if the bucket has no events.
+func (s *EventStore) headVersion(
+	ctx context.Context,
+	aggregateID string,
+	bucket int64,
+) (interface{}, error) {
+	var head interface{}
+	err := s.iterate(s.events, ctx, cs.SelectParams{
+		ColumnValues: []cs.ColumnComparator{
+			cs.Comparator("aggregate_id", aggregateID).Eq(),
+			cs.Comparator("bucket", bucket).Eq(),
+		},
+		SelectColumns: []string{"head_version"},
+		Limit:         1,
+	}, func(row interface{}) error {
+		head = versionOrNil(row.(map[string]interface{})["head_version"])
+		return nil
+	})
+	return head, err
+}
+
+// Load reads the aggregate's event-stream, starting at fromVersion
+// (inclusive), in version order.
+func (s *EventStore) Load(
+	ctx context.Context,
+	aggregateID string,
+	fromVersion int64,
+) ([]Event, error) {
+	if fromVersion < 1 {
+		fromVersion = 1
+	}
+
+	events := []Event{}
+	for bucket := s.bucket(fromVersion); ; bucket++ {
+		count := 0
+		err := s.iterate(s.events, ctx, cs.SelectParams{
+			ColumnValues: []cs.ColumnComparator{
+				cs.Comparator("aggregate_id", aggregateID).Eq(),
+				cs.Comparator("bucket", bucket).Eq(),
+				cs.Comparator("version", fromVersion).GtOrEq(),
+			},
+			SelectColumns: []string{
+				"aggregate_id", "version", "event_type", "data", "timestamp",
+			},
+			ResultsBind: &Event{},
+		}, func(row interface{}) error {
+			events = append(events, *row.(*Event))
+			count++
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// Versions are contiguous, so the stream ends at a partial bucket
+		if count == 0 || events[len(events)-1].Version < (bucket+1)*s.bucketSize {
+			return events, nil
+		}
+	}
+}
+
+// SaveSnapshot stores the snapshot of aggregate-state. The older snapshots
+// are kept, and can be removed using the snapshots-table.
+func (s *EventStore) SaveSnapshot(snapshot Snapshot) error {
+	if snapshot.AggregateID == "" {
+		return errors.New("Snapshot requires an AggregateID")
+	}
+	if snapshot.Timestamp.IsZero() {
+		snapshot.Timestamp = time.Now()
+	}
+	return s.snapshots.InsertStruct(&snapshot, cs.WriteParams{})
+}
+
+// LatestSnapshot returns the aggregate's snapshot with highest version.
+// Returns ErrNoSnapshot if the aggregate has no snapshots.
+func (s *EventStore) LatestSnapshot(
+	ctx context.Context,
+	aggregateID string,
+) (*Snapshot, error) {
+	var snapshot *Snapshot
+	err := s.iterate(s.snapshots, ctx, cs.SelectParams{
+		ColumnValues: []cs.ColumnComparator{
+			cs.Comparator("aggregate_id", aggregateID).Eq(),
+		},
+		Limit:       1,
+		ResultsBind: &Snapshot{},
+	}, func(row interface{}) error {
+		snapshot = row.(*Snapshot)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		return nil, ErrNoSnapshot
+	}
+	return snapshot, nil
+}
+
+// bucket returns the partition-bucket of version.
+func (s *EventStore) bucket(version int64) int64 {
+	return (version - 1) / s.bucketSize
+}
+
+// versionOrNil returns the version as read from database, nil if it's
+// not set (which is read as null or zero).
+func versionOrNil(v interface{}) interface{} {
+	version, isInt := v.(int64)
+	if !isInt || version == 0 {
+		return nil
+	}
+	return version
+}
@@ -0,0 +1,13 @@
+package eventstore
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestEventStore(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "EventStore Suite")
+}
@@ -0,0 +1,302 @@
+package eventstore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	cs "github.com/TerrexTech/go-cassandrautils/cassandra"
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	"github.com/TerrexTech/go-commonutils/utils"
+	cql "github.com/gocql/gocql"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("EventStore", func() {
+	var (
+		stmts    []string
+		values   [][]interface{}
+		session  *mocks.Session
+		keyspace *cs.Keyspace
+	)
+
+	BeforeEach(func() {
+		stmts = []string{}
+		values = [][]interface{}{}
+		session = &mocks.Session{
+			MockQuery: func(s string, v ...interface{}) {
+				stmts = append(stmts, utils.StandardizeSpaces(s))
+				values = append(values, v)
+			},
+		}
+		var err error
+		keyspace, err = cs.NewKeyspace(session, cs.KeyspaceConfig{
+			Name:        "test",
+			Replication: cs.SimpleStrategy{ReplicationFactor: 1},
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	newStore := func(c Config) *EventStore {
+		c.Keyspace = keyspace
+		s, err := New(session, c)
+		Expect(err).ToNot(HaveOccurred())
+		stmts = []string{}
+		values = [][]interface{}{}
+		return s
+	}
+
+	It("should create the events and snapshots tables", func() {
+		stmts = []string{}
+		s, err := New(session, Config{
+			Keyspace:       keyspace,
+			SnapshotsTable: "aggregate_snapshots",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(s.EventsTable().FullName()).To(Equal("test.events"))
+		Expect(s.SnapshotsTable().FullName()).To(Equal("test.aggregate_snapshots"))
+
+		Expect(stmts).To(HaveLen(2))
+		Expect(stmts[0]).To(ContainSubstring(
+			"PRIMARY KEY ((aggregate_id, bucket), version)",
+		))
+		Expect(stmts[0]).To(ContainSubstring("head_version bigint static"))
+		Expect(stmts[1]).To(ContainSubstring("CLUSTERING ORDER BY (version DESC)"))
+
+		_, err = New(session, Config{Keyspace: keyspace, BucketSize: -1})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should append events with next versions", func() {
+		s := newStore(Config{})
+		ts := time.Now()
+
+		events, err := s.Append(context.Background(), "a1", 0, []Event{
+			Event{EventType: "created", Data: []byte("{}"), Timestamp: ts},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(events).To(Equal([]Event{
+			Event{
+				AggregateID: "a1",
+				Version:     1,
+				EventType:   "created",
+				Data:        []byte("{}"),
+				Timestamp:   ts,
+			},
+		}))
+		Expect(stmts).To(Equal([]string{
+			"BEGIN BATCH UPDATE test.events SET head_version = ?" +
+				" WHERE aggregate_id = ? AND bucket = ? IF head_version = null; " +
+				"INSERT INTO test.events " +
+				"(aggregate_id,bucket,version,event_type,data,timestamp) " +
+				"VALUES (?,?,?,?,?,?); APPLY BATCH",
+		}))
+		Expect(values[0]).To(Equal([]interface{}{
+			int64(1), "a1", int64(0),
+			"a1", int64(0), int64(1), "created", []byte("{}"), ts,
+		}))
+	})
+
+	It("should condition the append on bucket's head-version", func() {
+		s := newStore(Config{BucketSize: 4})
+		serial := cql.SerialConsistency(0)
+		session.MockQuerySerialConsistency = func(cons cql.SerialConsistency) {
+			serial = cons
+		}
+
+		events, err := s.Append(context.Background(), "a1", 1, []Event{
+			Event{EventType: "e2"},
+			Event{EventType: "e3"},
+			Event{EventType: "e4"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(events).To(HaveLen(3))
+		Expect(events[2].Version).To(Equal(int64(4)))
+		Expect(events[2].Timestamp.IsZero()).To(BeFalse())
+		Expect(serial).To(Equal(cql.Serial))
+
+		Expect(stmts).To(HaveLen(1))
+		Expect(stmts[0]).To(ContainSubstring("IF head_version = ?;"))
+		Expect(strings.Count(stmts[0], "INSERT")).To(Equal(3))
+		Expect(values[0]).To(HaveLen(22))
+		Expect(values[0][:4]).To(Equal([]interface{}{
+			int64(4), "a1", int64(0), int64(1),
+		}))
+		Expect(values[0][17:19]).To(Equal([]interface{}{int64(0), int64(4)}))
+	})
+
+	It("should check previous bucket's head-version when starting a bucket", func() {
+		s := newStore(Config{BucketSize: 2})
+		var head interface{}
+		s.iterate = func(
+			table *cs.Table,
+			ctx context.Context,
+			p cs.SelectParams,
+			fn func(row interface{}) error,
+		) error {
+			Expect(p.ColumnValues[1].Value).To(Equal(int64(0)))
+			Expect(p.SelectColumns).To(Equal([]string{"head_version"}))
+			if head == nil {
+				return nil
+			}
+			return fn(map[string]interface{}{"head_version": head})
+		}
+
+		head = int64(2)
+		_, err := s.Append(context.Background(), "a1", 2, []Event{
+			Event{EventType: "e3"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stmts[0]).To(ContainSubstring("IF head_version = null;"))
+		Expect(values[0][:3]).To(Equal([]interface{}{int64(3), "a1", int64(1)}))
+
+		// Stream is behind the expected version
+		stmts = []string{}
+		head = int64(1)
+		_, err = s.Append(context.Background(), "a1", 2, []Event{
+			Event{EventType: "e3"},
+		})
+		Expect(err).To(Equal(&cs.ErrConcurrentModification{
+			Expected: int64(2),
+			Current:  int64(1),
+		}))
+		head = nil
+		_, err = s.Append(context.Background(), "a1", 2, []Event{
+			Event{EventType: "e3"},
+		})
+		Expect(err).To(Equal(&cs.ErrConcurrentModification{
+			Expected: int64(2),
+			Current:  nil,
+		}))
+		Expect(stmts).To(BeEmpty())
+	})
+
+	It("should return ErrConcurrentModification on version conflict", func() {
+		s := newStore(Config{})
+		session.MockQueryMapScanCAS = func(dest map[string]interface{}) (bool, error) {
+			dest["head_version"] = int64(3)
+			return false, nil
+		}
+
+		_, err := s.Append(context.Background(), "a1", 2, []Event{
+			Event{EventType: "e3"},
+		})
+		Expect(err).To(Equal(&cs.ErrConcurrentModification{
+			Expected: int64(2),
+			Current:  int64(3),
+		}))
+	})
+
+	It("should reject appends spanning buckets", func() {
+		s := newStore(Config{BucketSize: 2})
+		_, err := s.Append(context.Background(), "a1", 1, []Event{
+			Event{EventType: "e2"},
+			Event{EventType: "e3"},
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(stmts).To(BeEmpty())
+	})
+
+	It("should return error on invalid appends", func() {
+		s := newStore(Config{})
+		_, err := s.Append(context.Background(), "a1", 0, nil)
+		Expect(err).To(HaveOccurred())
+		_, err = s.Append(context.Background(), "a1", -1, []Event{Event{}})
+		Expect(err).To(HaveOccurred())
+		Expect(stmts).To(BeEmpty())
+	})
+
+	It("should save snapshots", func() {
+		s := newStore(Config{})
+		ts := time.Now()
+
+		err := s.SaveSnapshot(Snapshot{
+			AggregateID: "a1",
+			Version:     10,
+			Data:        []byte("{}"),
+			Timestamp:   ts,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stmts).To(Equal([]string{
+			"INSERT INTO test.snapshots (aggregate_id,data,timestamp,version) " +
+				"VALUES (?,?,?,?)",
+		}))
+		Expect(values[0]).To(Equal([]interface{}{"a1", []byte("{}"), ts, int64(10)}))
+
+		Expect(s.SaveSnapshot(Snapshot{})).To(HaveOccurred())
+	})
+
+	It("should load events from all buckets", func() {
+		s := newStore(Config{BucketSize: 2})
+		stored := map[int64][]Event{
+			0: []Event{Event{Version: 1}, Event{Version: 2}},
+			1: []Event{Event{Version: 3}, Event{Version: 4}},
+			2: []Event{Event{Version: 5}},
+		}
+		buckets := []int64{}
+		s.iterate = func(
+			table *cs.Table,
+			ctx context.Context,
+			p cs.SelectParams,
+			fn func(row interface{}) error,
+		) error {
+			Expect(table).To(Equal(s.EventsTable()))
+			bucket := p.ColumnValues[1].Value.(int64)
+			buckets = append(buckets, bucket)
+			from := p.ColumnValues[2].Value.(int64)
+			for _, event := range stored[bucket] {
+				if event.Version >= from {
+					e := event
+					err := fn(&e)
+					if err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+
+		events, err := s.Load(context.Background(), "a1", 2)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(events).To(Equal([]Event{
+			Event{Version: 2}, Event{Version: 3}, Event{Version: 4}, Event{Version: 5},
+		}))
+		Expect(buckets).To(Equal([]int64{0, 1, 2}))
+
+		// Stream ending at a full bucket
+		delete(stored, 2)
+		buckets = []int64{}
+		events, err = s.Load(context.Background(), "a1", 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(events).To(HaveLen(4))
+		Expect(buckets).To(Equal([]int64{0, 1, 2}))
+	})
+
+	It("should return the latest snapshot", func() {
+		s := newStore(Config{})
+		var snapshot *Snapshot
+		s.iterate = func(
+			table *cs.Table,
+			ctx context.Context,
+			p cs.SelectParams,
+			fn func(row interface{}) error,
+		) error {
+			Expect(table).To(Equal(s.SnapshotsTable()))
+			Expect(p.ColumnValues[0].Value).To(Equal("a1"))
+			Expect(p.Limit).To(Equal(uint(1)))
+			if snapshot == nil {
+				return nil
+			}
+			return fn(snapshot)
+		}
+
+		_, err := s.LatestSnapshot(context.Background(), "a1")
+		Expect(err).To(Equal(ErrNoSnapshot))
+
+		snapshot = &Snapshot{AggregateID: "a1", Version: 10}
+		latest, err := s.LatestSnapshot(context.Background(), "a1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(latest).To(Equal(snapshot))
+	})
+})
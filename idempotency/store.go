@@ -0,0 +1,270 @@
+// Package idempotency deduplicates retried requests (such as API requests
+// or messages), by recording their idempotency-keys with the response of
+// first request.
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	cs "github.com/TerrexTech/go-cassandrautils/cassandra"
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+	cql "github.com/gocql/gocql"
+)
+
+// ErrInProgress is returned by #Do if the first request with same key
+// is still being processed.
+var ErrInProgress = errors.New("Request with idempotency-key is in progress")
+
+// ErrNotPending is returned by #Complete and #Release if the key isn't
+// claimed, or is already completed.
+var ErrNotPending = errors.New("Idempotency-key is not pending")
+
+// Config defines configuration for Store.
+type Config struct {
+	Keyspace *cs.Keyspace
+	// Name of keys-table, defaults to "idempotency_keys"
+	Table string
+	// Time for which the completed keys are retained, defaults to 24h.
+	// This is rounded down to seconds.
+	TTL time.Duration
+	// Time for which the claimed keys are retained until completed, after
+	// which the key can be claimed again (such as if the process handling
+	// the request crashed). Defaults to 5 minutes (or TTL, if less).
+	// This is rounded down to seconds, and cannot be more than TTL.
+	PendingTTL time.Duration
+	// Consistency for queries, session-default if not set
+	Consistency cql.Consistency
+	// Consistency for serial-phase of queries, defaults to gocql.Serial
+	SerialConsistency cql.SerialConsistency
+}
+
+// Record is the stored state of an idempotency-key.
+type Record struct {
+	Key string
+	// Response of first request, set once completed
+	Response  []byte
+	Completed bool
+	// Time the key was claimed, in milliseconds precision. This identifies
+	// the claim in #Complete and #Release, since the key can be claimed
+	// again after the claim expires.
+	CreatedAt time.Time
+}
+
+// Store records the idempotency-keys.
+type Store struct {
+	consistency       cql.Consistency
+	pendingTTL        time.Duration
+	serialConsistency cql.SerialConsistency
+	table             *cs.Table
+	ttl               time.Duration
+}
+
+// NewStore creates the keys-table (if it doesn't exist), and returns a Store
+// using it.
+func NewStore(session driver.SessionI, c Config) (*Store, error) {
+	if c.Table == "" {
+		c.Table = "idempotency_keys"
+	}
+	if c.TTL == 0 {
+		c.TTL = 24 * time.Hour
+	}
+	if c.TTL < time.Second {
+		return nil, errors.New("TTL must be at least a second")
+	}
+	if c.PendingTTL == 0 {
+		c.PendingTTL = 5 * time.Minute
+		if c.PendingTTL > c.TTL {
+			c.PendingTTL = c.TTL
+		}
+	}
+	if c.PendingTTL < time.Second || c.PendingTTL > c.TTL {
+		return nil, errors.New("PendingTTL must be at least a second, and at most TTL")
+	}
+	if c.SerialConsistency == 0 {
+		c.SerialConsistency = cql.Serial
+	}
+
+	table, err := cs.NewTable(session, &cs.TableConfig{
+		Keyspace: c.Keyspace,
+		Name:     c.Table,
+	}, &map[string]cs.TableColumn{
+		"key": cs.TableColumn{
+			Name:            "key",
+			DataType:        "text",
+			PrimaryKeyIndex: "0",
+		},
+		"response": cs.TableColumn{
+			Name:     "response",
+			DataType: "blob",
+		},
+		"completed": cs.TableColumn{
+			Name:     "completed",
+			DataType: "boolean",
+		},
+		"createdAt": cs.TableColumn{
+			Name:     "created_at",
+			DataType: "timestamp",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{
+		consistency:       c.Consistency,
+		pendingTTL:        c.PendingTTL,
+		serialConsistency: c.SerialConsistency,
+		table:             table,
+		ttl:               c.TTL,
+	}, nil
+}
+
+// Table returns the keys-table.
+func (s *Store) Table() *cs.Table {
+	return s.table
+}
+
+// Claim records the key as pending (for PendingTTL), if it isn't recorded
+// yet, or its claim expired. Returns claimed as true along with the pending
+// record (to be passed to #Complete or #Release) if the key was recorded,
+// so the request should be processed, otherwise returns the existing record.
+func (s *Store) Claim(ctx context.Context, key string) (bool, *Record, error) {
+	stmt := fmt.Sprintf(
+		"INSERT INTO %s (key,completed,created_at) VALUES (?,?,?) IF NOT EXISTS "+
+			"USING TTL ? ",
+		s.table.FullName(),
+	)
+	// Same as the precision of timestamps in database
+	createdAt := time.Now().Truncate(time.Millisecond)
+	current := make(map[string]interface{})
+	claimed, err := s.execCAS(
+		ctx, stmt, current, key, false, createdAt, ttlSeconds(s.pendingTTL),
+	)
+	if err != nil {
+		return false, nil, err
+	}
+	if claimed {
+		return true, &Record{Key: key, CreatedAt: createdAt}, nil
+	}
+
+	record := &Record{
+		Key: key,
+	}
+	record.Response, _ = current["response"].([]byte)
+	record.Completed, _ = current["completed"].(bool)
+	record.CreatedAt, _ = current["created_at"].(time.Time)
+	return false, record, nil
+}
+
+// Complete stores the response for the claim (the record returned by
+// #Claim), and marks the key completed, so it is retained for TTL.
+// Returns ErrNotPending if the claim expired (even if the key was claimed
+// again), or the key is already completed.
+func (s *Store) Complete(ctx context.Context, claim *Record, response []byte) error {
+	// All columns are written to extend their TTL
+	stmt := fmt.Sprintf(
+		"UPDATE %s USING TTL ? SET response=?,completed=?,created_at=? "+
+			"WHERE key=? IF completed=? AND created_at=? ",
+		s.table.FullName(),
+	)
+	applied, err := s.execCAS(
+		ctx,
+		stmt,
+		map[string]interface{}{},
+		ttlSeconds(s.ttl),
+		response,
+		true,
+		claim.CreatedAt,
+		claim.Key,
+		false,
+		claim.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return ErrNotPending
+	}
+	return nil
+}
+
+// Release removes the claimed key (such as when the request fails), so it
+// can be claimed again. The claim is the record returned by #Claim.
+// Returns ErrNotPending if the claim expired, or the key is already completed.
+func (s *Store) Release(ctx context.Context, claim *Record) error {
+	stmt := fmt.Sprintf(
+		"DELETE FROM %s WHERE key=? IF completed=? AND created_at=? ",
+		s.table.FullName(),
+	)
+	applied, err := s.execCAS(
+		ctx, stmt, map[string]interface{}{}, claim.Key, false, claim.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return ErrNotPending
+	}
+	return nil
+}
+
+// Do processes the request with key once, calling fn only if the key isn't
+// recorded, and stores its response. For duplicate requests, the stored
+// response is returned with duplicate as true. Returns ErrInProgress if
+// the first request is still being processed. If fn returns an error, the
+// key is released so the request can be retried.
+func (s *Store) Do(
+	ctx context.Context,
+	key string,
+	fn func() ([]byte, error),
+) ([]byte, bool, error) {
+	claimed, record, err := s.Claim(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if !claimed {
+		if !record.Completed {
+			return nil, true, ErrInProgress
+		}
+		return record.Response, true, nil
+	}
+
+	response, err := fn()
+	if err != nil {
+		// The request can be retried even if the key couldn't be released,
+		// once the claim expires
+		s.Release(ctx, record)
+		return nil, false, err
+	}
+	err = s.Complete(ctx, record, response)
+	if err != nil {
+		return nil, false, err
+	}
+	return response, false, nil
+}
+
+// execCAS executes the lightweight-transaction, with consistency-levels
+// from Config.
+func (s *Store) execCAS(
+	ctx context.Context,
+	stmt string,
+	current map[string]interface{},
+	values ...interface{},
+) (bool, error) {
+	q := s.table.Session().
+		Query(stmt, values...).
+		WithContext(ctx).
+		SerialConsistency(s.serialConsistency)
+	if s.consistency != 0 {
+		q.Consistency(s.consistency)
+	}
+	return q.MapScanCAS(current)
+}
+
+// ttlSeconds returns the TTL in seconds, as used in USING TTL clause.
+func ttlSeconds(ttl time.Duration) int {
+	return int(ttl / time.Second)
+}
@@ -0,0 +1,190 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	cs "github.com/TerrexTech/go-cassandrautils/cassandra"
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	"github.com/TerrexTech/go-commonutils/utils"
+	cql "github.com/gocql/gocql"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Store", func() {
+	var (
+		stmts       []string
+		values      [][]interface{}
+		consistency cql.Consistency
+		session     *mocks.Session
+		store       *Store
+	)
+
+	BeforeEach(func() {
+		stmts = []string{}
+		values = [][]interface{}{}
+		consistency = cql.Any
+		session = &mocks.Session{
+			MockQuery: func(s string, v ...interface{}) {
+				stmts = append(stmts, utils.StandardizeSpaces(s))
+				values = append(values, v)
+			},
+			MockQueryConsistency: func(c cql.Consistency) {
+				consistency = c
+			},
+		}
+		keyspace, err := cs.NewKeyspace(session, cs.KeyspaceConfig{
+			Name:        "test",
+			Replication: cs.SimpleStrategy{ReplicationFactor: 1},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		store, err = NewStore(session, Config{
+			Keyspace:    keyspace,
+			Table:       "request_keys",
+			TTL:         time.Hour,
+			PendingTTL:  time.Minute,
+			Consistency: cql.LocalQuorum,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(store.Table().FullName()).To(Equal("test.request_keys"))
+		_, err = NewStore(session, Config{Keyspace: keyspace, TTL: time.Millisecond})
+		Expect(err).To(HaveOccurred())
+		_, err = NewStore(session, Config{
+			Keyspace:   keyspace,
+			TTL:        time.Minute,
+			PendingTTL: time.Hour,
+		})
+		Expect(err).To(HaveOccurred())
+		stmts = []string{}
+		values = [][]interface{}{}
+	})
+
+	It("should process new keys and store the response", func() {
+		response, duplicate, err := store.Do(
+			context.Background(), "k1", func() ([]byte, error) {
+				return []byte("ok"), nil
+			},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(duplicate).To(BeFalse())
+		Expect(response).To(Equal([]byte("ok")))
+		Expect(consistency).To(Equal(cql.LocalQuorum))
+
+		Expect(stmts).To(Equal([]string{
+			"INSERT INTO test.request_keys (key,completed,created_at) VALUES (?,?,?) " +
+				"IF NOT EXISTS USING TTL ?",
+			"UPDATE test.request_keys USING TTL ? SET response=?,completed=?,created_at=? " +
+				"WHERE key=? IF completed=? AND created_at=?",
+		}))
+		Expect(values[0][:2]).To(Equal([]interface{}{"k1", false}))
+		Expect(values[0][3]).To(Equal(60))
+		createdAt := values[0][2].(time.Time)
+		Expect(createdAt).To(Equal(createdAt.Truncate(time.Millisecond)))
+		Expect(values[1]).To(Equal([]interface{}{
+			3600, []byte("ok"), true, createdAt, "k1", false, createdAt,
+		}))
+	})
+
+	It("should return the claim from Claim", func() {
+		claimed, claim, err := store.Claim(context.Background(), "k1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(claimed).To(BeTrue())
+		Expect(claim).To(Equal(&Record{Key: "k1", CreatedAt: values[0][2].(time.Time)}))
+	})
+
+	It("should not complete claims which expired and were claimed again", func() {
+		// Simulates the row for key, applying conditions on created_at
+		var row *Record
+		session.MockQueryMapScanCAS = func(dest map[string]interface{}) (bool, error) {
+			v := values[len(values)-1]
+			switch {
+			case strings.HasPrefix(stmts[len(stmts)-1], "INSERT"):
+				if row != nil {
+					dest["completed"] = row.Completed
+					dest["created_at"] = row.CreatedAt
+					return false, nil
+				}
+				row = &Record{Key: v[0].(string), CreatedAt: v[2].(time.Time)}
+				return true, nil
+			default:
+				if row == nil || row.Completed || !row.CreatedAt.Equal(v[6].(time.Time)) {
+					return false, nil
+				}
+				row.Completed = true
+				return true, nil
+			}
+		}
+		ctx := context.Background()
+
+		_, claimA, err := store.Claim(ctx, "k1")
+		Expect(err).ToNot(HaveOccurred())
+		// Claim A expires, and key is claimed again
+		row = nil
+		time.Sleep(2 * time.Millisecond)
+		claimed, claimB, err := store.Claim(ctx, "k1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(claimed).To(BeTrue())
+		Expect(claimB.CreatedAt.After(claimA.CreatedAt)).To(BeTrue())
+
+		Expect(store.Complete(ctx, claimA, []byte("a"))).To(Equal(ErrNotPending))
+		Expect(values[2][6]).To(Equal(claimA.CreatedAt))
+		Expect(row.Completed).To(BeFalse())
+
+		Expect(store.Complete(ctx, claimB, []byte("b"))).ToNot(HaveOccurred())
+		Expect(values[3][6]).To(Equal(claimB.CreatedAt))
+		Expect(row.Completed).To(BeTrue())
+	})
+
+	It("should return the stored response for duplicate keys", func() {
+		session.MockQueryMapScanCAS = func(dest map[string]interface{}) (bool, error) {
+			dest["key"] = "k1"
+			dest["response"] = []byte("ok")
+			dest["completed"] = true
+			return false, nil
+		}
+		response, duplicate, err := store.Do(
+			context.Background(), "k1", func() ([]byte, error) {
+				Fail("Duplicate request must not be processed")
+				return nil, nil
+			},
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(duplicate).To(BeTrue())
+		Expect(response).To(Equal([]byte("ok")))
+	})
+
+	It("should return ErrInProgress if first request is pending", func() {
+		session.MockQueryMapScanCAS = func(dest map[string]interface{}) (bool, error) {
+			dest["completed"] = false
+			return false, nil
+		}
+		_, duplicate, err := store.Do(
+			context.Background(), "k1", func() ([]byte, error) {
+				return nil, nil
+			},
+		)
+		Expect(err).To(Equal(ErrInProgress))
+		Expect(duplicate).To(BeTrue())
+
+		claim := &Record{Key: "k1", CreatedAt: time.Now()}
+		Expect(store.Complete(context.Background(), claim, nil)).To(Equal(ErrNotPending))
+		Expect(store.Release(context.Background(), claim)).To(Equal(ErrNotPending))
+	})
+
+	It("should release the key if processing fails", func() {
+		_, _, err := store.Do(
+			context.Background(), "k1", func() ([]byte, error) {
+				return nil, errors.New("some error")
+			},
+		)
+		Expect(err).To(HaveOccurred())
+		Expect(stmts[1]).To(Equal(
+			"DELETE FROM test.request_keys WHERE key=? IF completed=? AND created_at=?",
+		))
+		Expect(values[1]).To(Equal([]interface{}{"k1", false, values[0][2]}))
+	})
+})
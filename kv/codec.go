@@ -0,0 +1,75 @@
+package kv
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// Codec encodes the values stored in Store.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// The provided codecs.
+var (
+	// JSONCodec encodes values as JSON
+	JSONCodec Codec = jsonCodec{}
+	// GobCodec encodes values using encoding/gob
+	GobCodec Codec = gobCodec{}
+	// BytesCodec stores []byte and string values as is.
+	// Values are decoded into *[]byte or *string.
+	BytesCodec Codec = bytesCodec{}
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := gob.NewEncoder(buf).Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+type bytesCodec struct{}
+
+func (bytesCodec) Marshal(v interface{}) ([]byte, error) {
+	switch value := v.(type) {
+	case []byte:
+		// Copied, so the caller's slice isn't shared with the LRU cache
+		return append([]byte{}, value...), nil
+	case string:
+		return []byte(value), nil
+	}
+	return nil, fmt.Errorf("BytesCodec requires []byte or string value, found: %T", v)
+}
+
+func (bytesCodec) Unmarshal(data []byte, v interface{}) error {
+	switch dest := v.(type) {
+	case *[]byte:
+		*dest = append([]byte{}, data...)
+		return nil
+	case *string:
+		*dest = string(data)
+		return nil
+	}
+	return fmt.Errorf("BytesCodec requires *[]byte or *string destination, found: %T", v)
+}
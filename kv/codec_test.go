@@ -0,0 +1,41 @@
+package kv
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Codec", func() {
+	type item struct {
+		Name  string
+		Count int
+	}
+
+	It("should encode and decode values", func() {
+		for _, codec := range []Codec{JSONCodec, GobCodec} {
+			data, err := codec.Marshal(item{Name: "a", Count: 2})
+			Expect(err).ToNot(HaveOccurred())
+
+			decoded := item{}
+			Expect(codec.Unmarshal(data, &decoded)).To(Succeed())
+			Expect(decoded).To(Equal(item{Name: "a", Count: 2}))
+		}
+	})
+
+	It("should store bytes and strings as is", func() {
+		data, err := BytesCodec.Marshal("abc")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data).To(Equal([]byte("abc")))
+
+		var decoded []byte
+		Expect(BytesCodec.Unmarshal(data, &decoded)).To(Succeed())
+		Expect(decoded).To(Equal([]byte("abc")))
+		var decodedString string
+		Expect(BytesCodec.Unmarshal(data, &decodedString)).To(Succeed())
+		Expect(decodedString).To(Equal("abc"))
+
+		_, err = BytesCodec.Marshal(1)
+		Expect(err).To(HaveOccurred())
+		Expect(BytesCodec.Unmarshal(data, &item{})).To(HaveOccurred())
+	})
+})
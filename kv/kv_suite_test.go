@@ -0,0 +1,13 @@
+package kv
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestKV(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "KV Suite")
+}
@@ -0,0 +1,97 @@
+package kv
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lru is an in-process least-recently-used cache of encoded values.
+type lru struct {
+	entries map[string]*list.Element
+	lock    sync.Mutex
+	// Most-recently-used entries are in front
+	order *list.List
+	size  int
+}
+
+// lruEntry is an lru element-value.
+type lruEntry struct {
+	key    string
+	value  []byte
+	expiry time.Time
+}
+
+func newLRU(size int) *lru {
+	return &lru{
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		size:    size,
+	}
+}
+
+// get returns the key's value, if cached and not expired.
+func (c *lru) get(key string) ([]byte, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	elem, exists := c.entries[key]
+	if !exists {
+		return nil, false
+	}
+	entry := elem.Value.(*lruEntry)
+	if !time.Now().Before(entry.expiry) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.value, true
+}
+
+// set caches the key's value until expiry, evicting the least-recently-used
+// entry if the cache is full.
+func (c *lru) set(key string, value []byte, expiry time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if elem, exists := c.entries[key]; exists {
+		entry := elem.Value.(*lruEntry)
+		entry.value = value
+		entry.expiry = expiry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{
+		key:    key,
+		value:  value,
+		expiry: expiry,
+	})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// remove removes the keys from cache.
+func (c *lru) remove(keys ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, key := range keys {
+		if elem, exists := c.entries[key]; exists {
+			c.order.Remove(elem)
+			delete(c.entries, key)
+		}
+	}
+}
+
+// clear removes all entries from cache.
+func (c *lru) clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
@@ -0,0 +1,44 @@
+package kv
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("lru", func() {
+	expiry := time.Now().Add(time.Hour)
+
+	It("should evict the least-recently-used entries", func() {
+		c := newLRU(2)
+		c.set("a", []byte("1"), expiry)
+		c.set("b", []byte("2"), expiry)
+		_, cached := c.get("a")
+		Expect(cached).To(BeTrue())
+
+		c.set("c", []byte("3"), expiry)
+		_, cached = c.get("b")
+		Expect(cached).To(BeFalse())
+		value, cached := c.get("a")
+		Expect(cached).To(BeTrue())
+		Expect(value).To(Equal([]byte("1")))
+	})
+
+	It("should not return expired or removed entries", func() {
+		c := newLRU(2)
+		c.set("a", []byte("1"), time.Now().Add(-time.Second))
+		_, cached := c.get("a")
+		Expect(cached).To(BeFalse())
+
+		c.set("b", []byte("2"), expiry)
+		c.remove("b")
+		_, cached = c.get("b")
+		Expect(cached).To(BeFalse())
+
+		c.set("c", []byte("3"), expiry)
+		c.clear()
+		_, cached = c.get("c")
+		Expect(cached).To(BeFalse())
+	})
+})
@@ -0,0 +1,321 @@
+// Package kv provides a key/value cache stored in a Cassandra table,
+// with pluggable value-codecs and an optional in-process LRU cache.
+package kv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	cs "github.com/TerrexTech/go-cassandrautils/cassandra"
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+)
+
+// ErrNotFound is returned if the key doesn't exist, or has expired.
+var ErrNotFound = errors.New("Key not found")
+
+// Config defines configuration for Store.
+type Config struct {
+	Keyspace *cs.Keyspace
+	// Name of key/value table, defaults to "kv"
+	Table string
+	// Codec for values, defaults to JSONCodec
+	Codec Codec
+	// Max number of entries in the in-process LRU cache,
+	// which is disabled if this is zero.
+	LRUSize int
+	// Max time for which entries are kept in LRU cache, since they might be
+	// modified by other processes. Defaults to 1 minute. The entries are
+	// also not kept after their TTL.
+	LRUTTL time.Duration
+}
+
+// Store gets and sets the values by key. The values are encoded by Codec.
+type Store struct {
+	codec    Codec
+	cache    *lru
+	cacheTTL time.Duration
+	// This facilitates mocking by allowing overwriting this
+	fetch func(ctx context.Context, keys []string) ([]kvRow, error)
+	table *cs.Table
+}
+
+// kvRow is a row of key/value table.
+type kvRow struct {
+	Key   string `db:"key"`
+	Value []byte `db:"value"`
+	// Remaining time-to-live of value in seconds, nil if it doesn't expire
+	TTL *int `db:"ttl"`
+}
+
+// NewStore creates the key/value table (if it doesn't exist), and returns
+// a Store using it.
+func NewStore(session driver.SessionI, c Config) (*Store, error) {
+	if c.Table == "" {
+		c.Table = "kv"
+	}
+	if c.Codec == nil {
+		c.Codec = JSONCodec
+	}
+	if c.LRUSize < 0 {
+		return nil, errors.New("LRUSize cannot be negative")
+	}
+	if c.LRUTTL == 0 {
+		c.LRUTTL = time.Minute
+	}
+
+	table, err := cs.NewTable(session, &cs.TableConfig{
+		Keyspace: c.Keyspace,
+		Name:     c.Table,
+	}, &map[string]cs.TableColumn{
+		"key": cs.TableColumn{
+			Name:            "key",
+			DataType:        "text",
+			PrimaryKeyIndex: "0",
+		},
+		"value": cs.TableColumn{
+			Name:     "value",
+			DataType: "blob",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		codec:    c.Codec,
+		cacheTTL: c.LRUTTL,
+		table:    table,
+	}
+	if c.LRUSize > 0 {
+		s.cache = newLRU(c.LRUSize)
+	}
+	s.fetch = s.fetchValues
+	return s, nil
+}
+
+// Table returns the key/value table.
+func (s *Store) Table() *cs.Table {
+	return s.table
+}
+
+// Get gets the key's value, and decodes it into dest (a pointer).
+// Returns ErrNotFound if the key doesn't exist.
+func (s *Store) Get(ctx context.Context, key string, dest interface{}) error {
+	values, err := s.getValues(ctx, []string{key})
+	if err != nil {
+		return err
+	}
+	value, exists := values[key]
+	if !exists {
+		return ErrNotFound
+	}
+	return s.codec.Unmarshal(value, dest)
+}
+
+// GetMulti gets the values of keys, and decodes them into dest, which must
+// be a pointer to map with string keys (such as *map[string]User). The keys
+// that don't exist are not added to dest.
+func (s *Store) GetMulti(ctx context.Context, keys []string, dest interface{}) error {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr ||
+		destValue.Elem().Kind() != reflect.Map ||
+		destValue.Elem().Type().Key().Kind() != reflect.String {
+		return errors.New("GetMulti requires dest to be a pointer to map with string-keys")
+	}
+	destMap := destValue.Elem()
+	if destMap.IsNil() {
+		destMap.Set(reflect.MakeMap(destMap.Type()))
+	}
+
+	values, err := s.getValues(ctx, keys)
+	if err != nil {
+		return err
+	}
+	elemType := destMap.Type().Elem()
+	for key, value := range values {
+		elem := reflect.New(elemType)
+		err = s.codec.Unmarshal(value, elem.Interface())
+		if err != nil {
+			return fmt.Errorf("Error decoding value for key %s: %s", key, err)
+		}
+		mapKey := reflect.ValueOf(key).Convert(destMap.Type().Key())
+		destMap.SetMapIndex(mapKey, elem.Elem())
+	}
+	return nil
+}
+
+// Set encodes and stores the key's value. The key expires after ttl,
+// or never if ttl is zero. The ttl is rounded down to seconds.
+func (s *Store) Set(
+	ctx context.Context,
+	key string,
+	value interface{},
+	ttl time.Duration,
+) error {
+	if ttl < 0 {
+		return errors.New("TTL cannot be negative")
+	}
+	data, err := s.codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return s.setValue(ctx, key, data, ttl)
+}
+
+// Delete deletes the key.
+func (s *Store) Delete(ctx context.Context, key string) error {
+	s.Invalidate(key)
+	stmt := fmt.Sprintf("DELETE FROM %s WHERE key=? ", s.table.FullName())
+	err := s.table.Session().Query(stmt, key).WithContext(ctx).Exec()
+	// A concurrent #Get might have cached the old value during the write
+	s.Invalidate(key)
+	return err
+}
+
+// GetOrLoad gets the key's value (same as #Get), or if the key doesn't
+// exist, sets it to the value returned by loader (with ttl, see #Set).
+// The value is decoded into dest (a pointer) in both cases.
+func (s *Store) GetOrLoad(
+	ctx context.Context,
+	key string,
+	dest interface{},
+	ttl time.Duration,
+	loader func() (interface{}, error),
+) error {
+	if ttl < 0 {
+		return errors.New("TTL cannot be negative")
+	}
+	err := s.Get(ctx, key, dest)
+	if err != ErrNotFound {
+		return err
+	}
+
+	value, err := loader()
+	if err != nil {
+		return err
+	}
+	data, err := s.codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+	err = s.setValue(ctx, key, data, ttl)
+	if err != nil {
+		return err
+	}
+	return s.codec.Unmarshal(data, dest)
+}
+
+// Invalidate removes the keys from in-process LRU cache, such as when
+// they are modified by other processes. The keys are removed from the
+// cache of this Store only.
+func (s *Store) Invalidate(keys ...string) {
+	if s.cache != nil {
+		s.cache.remove(keys...)
+	}
+}
+
+// InvalidateAll removes all keys from in-process LRU cache.
+func (s *Store) InvalidateAll() {
+	if s.cache != nil {
+		s.cache.clear()
+	}
+}
+
+// getValues returns the encoded values of keys, from LRU cache if cached,
+// else from table. The keys that don't exist are not included.
+func (s *Store) getValues(
+	ctx context.Context,
+	keys []string,
+) (map[string][]byte, error) {
+	values := make(map[string][]byte)
+	missing := []string{}
+	for _, key := range keys {
+		if s.cache != nil {
+			if value, cached := s.cache.get(key); cached {
+				values[key] = value
+				continue
+			}
+		}
+		missing = append(missing, key)
+	}
+	if len(missing) == 0 {
+		return values, nil
+	}
+
+	rows, err := s.fetch(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		values[row.Key] = row.Value
+		var ttl time.Duration
+		if row.TTL != nil {
+			ttl = time.Duration(*row.TTL) * time.Second
+		}
+		s.cacheValue(row.Key, row.Value, ttl)
+	}
+	return values, nil
+}
+
+// setValue stores the encoded value, and caches it in LRU cache.
+func (s *Store) setValue(
+	ctx context.Context,
+	key string,
+	data []byte,
+	ttl time.Duration,
+) error {
+	s.Invalidate(key)
+	stmt := fmt.Sprintf(
+		"INSERT INTO %s (key,value) VALUES (?,?) USING TTL ? ", s.table.FullName(),
+	)
+	err := s.table.Session().
+		Query(stmt, key, data, int(ttl/time.Second)).
+		WithContext(ctx).
+		Exec()
+	if err != nil {
+		// A concurrent #Get might have cached the old value during the
+		// write, which might still have been applied
+		s.Invalidate(key)
+		return err
+	}
+	// This also replaces any old value cached during the write
+	s.cacheValue(key, data, ttl)
+	return nil
+}
+
+// cacheValue caches the encoded value in LRU cache, for LRUTTL or ttl
+// (if not zero), whichever is less, so the value isn't cached after it
+// expires in table.
+func (s *Store) cacheValue(key string, data []byte, ttl time.Duration) {
+	if s.cache == nil {
+		return
+	}
+	cacheTTL := s.cacheTTL
+	if ttl > 0 && ttl < cacheTTL {
+		cacheTTL = ttl
+	}
+	s.cache.set(key, data, time.Now().Add(cacheTTL))
+}
+
+// fetchValues reads the values of keys from table, with their TTL.
+func (s *Store) fetchValues(ctx context.Context, keys []string) ([]kvRow, error) {
+	rows := []kvRow{}
+	err := s.table.Iterate(ctx, cs.SelectParams{
+		ColumnValues: []cs.ColumnComparator{
+			cs.Comparator("key", keys).In(),
+		},
+		SelectExpressions: []cs.SelectExpression{
+			cs.SelectColumn("key"),
+			cs.SelectColumn("value"),
+			cs.TTL("value").As("ttl"),
+		},
+		ResultsBind: &kvRow{},
+	}, func(row interface{}) error {
+		rows = append(rows, *row.(*kvRow))
+		return nil
+	})
+	return rows, err
+}
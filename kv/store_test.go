@@ -0,0 +1,233 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	cs "github.com/TerrexTech/go-cassandrautils/cassandra"
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	"github.com/TerrexTech/go-commonutils/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Store", func() {
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	var (
+		stmts    []string
+		values   [][]interface{}
+		fetched  [][]string
+		stored   map[string][]byte
+		ttls     map[string]int
+		session  *mocks.Session
+		keyspace *cs.Keyspace
+	)
+
+	BeforeEach(func() {
+		session = &mocks.Session{
+			MockQuery: func(s string, v ...interface{}) {
+				stmts = append(stmts, utils.StandardizeSpaces(s))
+				values = append(values, v)
+			},
+		}
+		var err error
+		keyspace, err = cs.NewKeyspace(session, cs.KeyspaceConfig{
+			Name:        "test",
+			Replication: cs.SimpleStrategy{ReplicationFactor: 1},
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	newStore := func(c Config) *Store {
+		c.Keyspace = keyspace
+		s, err := NewStore(session, c)
+		Expect(err).ToNot(HaveOccurred())
+
+		stmts = []string{}
+		values = [][]interface{}{}
+		fetched = [][]string{}
+		stored = map[string][]byte{
+			"u1": []byte(`{"name":"A"}`),
+			"u2": []byte(`{"name":"B"}`),
+		}
+		ttls = map[string]int{}
+		s.fetch = func(ctx context.Context, keys []string) ([]kvRow, error) {
+			fetched = append(fetched, keys)
+			rows := []kvRow{}
+			for _, key := range keys {
+				if value, exists := stored[key]; exists {
+					row := kvRow{Key: key, Value: value}
+					if ttl, expires := ttls[key]; expires {
+						row.TTL = &ttl
+					}
+					rows = append(rows, row)
+				}
+			}
+			return rows, nil
+		}
+		return s
+	}
+
+	It("should validate the config", func() {
+		_, err := NewStore(session, Config{Keyspace: keyspace, LRUSize: -1})
+		Expect(err).To(HaveOccurred())
+
+		s := newStore(Config{Table: "cache"})
+		Expect(s.Table().FullName()).To(Equal("test.cache"))
+	})
+
+	It("should get the decoded values", func() {
+		s := newStore(Config{})
+
+		u := user{}
+		Expect(s.Get(context.Background(), "u1", &u)).To(Succeed())
+		Expect(u).To(Equal(user{Name: "A"}))
+		Expect(s.Get(context.Background(), "u3", &u)).To(Equal(ErrNotFound))
+
+		users := map[string]user{}
+		err := s.GetMulti(context.Background(), []string{"u1", "u2", "u3"}, &users)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(users).To(Equal(map[string]user{
+			"u1": user{Name: "A"},
+			"u2": user{Name: "B"},
+		}))
+		Expect(fetched).To(HaveLen(3))
+
+		err = s.GetMulti(context.Background(), []string{"u1"}, users)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should set and delete the values", func() {
+		s := newStore(Config{Codec: BytesCodec})
+
+		err := s.Set(context.Background(), "k1", "v1", 90*time.Second)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(s.Set(context.Background(), "k1", "v1", -1)).To(HaveOccurred())
+		Expect(s.Set(context.Background(), "k1", 1, 0)).To(HaveOccurred())
+
+		Expect(s.Delete(context.Background(), "k1")).To(Succeed())
+		Expect(stmts).To(Equal([]string{
+			"INSERT INTO test.kv (key,value) VALUES (?,?) USING TTL ?",
+			"DELETE FROM test.kv WHERE key=?",
+		}))
+		Expect(values).To(Equal([][]interface{}{
+			[]interface{}{"k1", []byte("v1"), 90},
+			[]interface{}{"k1"},
+		}))
+	})
+
+	It("should load and set the missing values", func() {
+		s := newStore(Config{})
+
+		u := user{}
+		loads := 0
+		loader := func() (interface{}, error) {
+			loads++
+			return user{Name: "C"}, nil
+		}
+		err := s.GetOrLoad(context.Background(), "u3", &u, time.Minute, loader)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(u).To(Equal(user{Name: "C"}))
+		Expect(values).To(Equal([][]interface{}{
+			[]interface{}{"u3", []byte(`{"name":"C"}`), 60},
+		}))
+
+		err = s.GetOrLoad(context.Background(), "u1", &u, time.Minute, loader)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(u).To(Equal(user{Name: "A"}))
+		Expect(loads).To(Equal(1))
+
+		err = s.GetOrLoad(context.Background(), "u4", &u, 0, func() (interface{}, error) {
+			return nil, errors.New("some error")
+		})
+		Expect(err).To(HaveOccurred())
+
+		err = s.GetOrLoad(context.Background(), "u4", &u, -1, loader)
+		Expect(err).To(HaveOccurred())
+		Expect(loads).To(Equal(1))
+	})
+
+	It("should cache the values in LRU cache", func() {
+		s := newStore(Config{LRUSize: 10})
+
+		u := user{}
+		Expect(s.Get(context.Background(), "u1", &u)).To(Succeed())
+		Expect(s.Get(context.Background(), "u1", &u)).To(Succeed())
+		Expect(fetched).To(Equal([][]string{[]string{"u1"}}))
+
+		Expect(s.Set(context.Background(), "u2", user{Name: "D"}, 0)).To(Succeed())
+		users := map[string]user{}
+		err := s.GetMulti(context.Background(), []string{"u1", "u2", "u3"}, &users)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(users["u2"]).To(Equal(user{Name: "D"}))
+		Expect(fetched[1]).To(Equal([]string{"u3"}))
+
+		s.Invalidate("u1")
+		Expect(s.Get(context.Background(), "u1", &u)).To(Succeed())
+		Expect(fetched[2]).To(Equal([]string{"u1"}))
+
+		Expect(s.Delete(context.Background(), "u2")).To(Succeed())
+		delete(stored, "u2")
+		Expect(s.Get(context.Background(), "u2", &u)).To(Equal(ErrNotFound))
+
+		s.InvalidateAll()
+		Expect(s.Get(context.Background(), "u1", &u)).To(Succeed())
+		Expect(fetched).To(HaveLen(5))
+	})
+
+	It("should not cache the values read during the write", func() {
+		s := newStore(Config{LRUSize: 10})
+
+		// Concurrent Get, before the write is applied
+		u := user{}
+		session.MockQueryWithContext = func(ctx context.Context) {
+			Expect(s.Get(ctx, "u1", &u)).To(Succeed())
+			Expect(s.Get(ctx, "u2", &u)).To(Succeed())
+		}
+		Expect(s.Delete(context.Background(), "u1")).To(Succeed())
+		delete(stored, "u1")
+		session.MockQueryWithContext = nil
+		Expect(s.Get(context.Background(), "u1", &u)).To(Equal(ErrNotFound))
+
+		// The failed write might still have been applied
+		session.MockQueryWithContext = func(ctx context.Context) {
+			Expect(s.Get(ctx, "u2", &u)).To(Succeed())
+		}
+		session.MockQueryExecError = "some error"
+		err := s.Set(context.Background(), "u2", user{Name: "D"}, 0)
+		Expect(err).To(HaveOccurred())
+		stored["u2"] = []byte(`{"name":"D"}`)
+		session.MockQueryWithContext = nil
+		Expect(s.Get(context.Background(), "u2", &u)).To(Succeed())
+		Expect(u).To(Equal(user{Name: "D"}))
+	})
+
+	It("should not cache the values after their TTL", func() {
+		s := newStore(Config{LRUSize: 10})
+		ttls["u1"] = 1
+
+		u := user{}
+		Expect(s.Get(context.Background(), "u1", &u)).To(Succeed())
+		Expect(s.Get(context.Background(), "u2", &u)).To(Succeed())
+		expiresIn := func(key string) time.Duration {
+			return time.Until(s.cache.entries[key].Value.(*lruEntry).expiry)
+		}
+		Expect(expiresIn("u1")).To(BeNumerically("~", time.Second, time.Second/10))
+		Expect(expiresIn("u2")).To(BeNumerically("~", time.Minute, time.Second/10))
+	})
+
+	It("should not share the set value with LRU cache", func() {
+		s := newStore(Config{Codec: BytesCodec, LRUSize: 10})
+
+		value := []byte("v1")
+		Expect(s.Set(context.Background(), "k1", value, 0)).To(Succeed())
+		value[0] = 'x'
+		var cached []byte
+		Expect(s.Get(context.Background(), "k1", &cached)).To(Succeed())
+		Expect(cached).To(Equal([]byte("v1")))
+	})
+})
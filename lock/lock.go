@@ -0,0 +1,235 @@
+// Package lock provides distributed named-locks, using lightweight-
+// transactions on a locks-table. The locks expire after a TTL unless
+// renewed, so a crashed owner doesn't hold a lock forever.
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	cs "github.com/TerrexTech/go-cassandrautils/cassandra"
+	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
+	cql "github.com/gocql/gocql"
+)
+
+// ErrLockHeld is returned by #Acquire if the lock is held by another owner.
+var ErrLockHeld = errors.New("Lock is held by another owner")
+
+// ErrLockNotHeld is returned by #Release if the lock isn't held by owner,
+// such as when it expired and was acquired by another owner.
+var ErrLockNotHeld = errors.New("Lock is not held by owner")
+
+// Config defines configuration for Locker.
+type Config struct {
+	Keyspace *cs.Keyspace
+	// Name of locks-table, defaults to "locks"
+	Table string
+	// Time after which a lock expires if not renewed, defaults to 30s.
+	// This is rounded down to seconds.
+	TTL time.Duration
+	// Interval for renewing the acquired locks, defaults to TTL/3
+	RenewInterval time.Duration
+	// Consistency for serial-phase of lock-operations, defaults to gocql.Serial
+	SerialConsistency cql.SerialConsistency
+}
+
+// Locker acquires the named-locks.
+type Locker struct {
+	renewInterval     time.Duration
+	serialConsistency cql.SerialConsistency
+	table             *cs.Table
+	ttl               time.Duration
+}
+
+// Lock is an acquired lock, which is renewed in background until released.
+type Lock struct {
+	cancel context.CancelFunc
+	// Closed when renewal goroutine exits
+	done   chan struct{}
+	locker *Locker
+	lost   chan struct{}
+	name   string
+	owner  string
+}
+
+// NewLocker creates the locks-table (if it doesn't exist), and returns
+// a Locker using it.
+func NewLocker(session driver.SessionI, c Config) (*Locker, error) {
+	if c.Table == "" {
+		c.Table = "locks"
+	}
+	if c.TTL == 0 {
+		c.TTL = 30 * time.Second
+	}
+	if c.TTL < time.Second {
+		return nil, errors.New("TTL must be at least a second")
+	}
+	// Same as the TTL in database, so the lock isn't
+	// considered held after it expired in database
+	c.TTL = c.TTL.Truncate(time.Second)
+	if c.RenewInterval == 0 {
+		c.RenewInterval = c.TTL / 3
+	}
+	if c.RenewInterval < 0 || c.RenewInterval >= c.TTL {
+		return nil, errors.New("RenewInterval must be positive, and less than TTL")
+	}
+	if c.SerialConsistency == 0 {
+		c.SerialConsistency = cql.Serial
+	}
+
+	table, err := cs.NewTable(session, &cs.TableConfig{
+		Keyspace: c.Keyspace,
+		Name:     c.Table,
+	}, &map[string]cs.TableColumn{
+		"name": cs.TableColumn{
+			Name:            "name",
+			DataType:        "text",
+			PrimaryKeyIndex: "0",
+		},
+		"owner": cs.TableColumn{
+			Name:     "owner",
+			DataType: "text",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Locker{
+		renewInterval:     c.RenewInterval,
+		serialConsistency: c.SerialConsistency,
+		table:             table,
+		ttl:               c.TTL,
+	}, nil
+}
+
+// Table returns the locks-table.
+func (l *Locker) Table() *cs.Table {
+	return l.table
+}
+
+// Acquire acquires the named-lock for owner (such as a unique instance-ID),
+// and starts renewing it in background. Returns ErrLockHeld if the lock is
+// held by another owner.
+func (l *Locker) Acquire(ctx context.Context, name string, owner string) (*Lock, error) {
+	stmt := fmt.Sprintf(
+		"INSERT INTO %s (name,owner) VALUES (?,?) IF NOT EXISTS USING TTL ? ",
+		l.table.FullName(),
+	)
+	// The TTL starts when database applies the query, which is after now
+	acquiredAt := time.Now()
+	applied, err := l.execCAS(ctx, stmt, name, owner, l.ttlSeconds())
+	if err != nil {
+		return nil, err
+	}
+	if !applied {
+		return nil, ErrLockHeld
+	}
+
+	renewCtx, cancel := context.WithCancel(context.Background())
+	lock := &Lock{
+		cancel: cancel,
+		done:   make(chan struct{}),
+		locker: l,
+		lost:   make(chan struct{}),
+		name:   name,
+		owner:  owner,
+	}
+	go lock.renew(renewCtx, acquiredAt.Add(l.ttl))
+	return lock, nil
+}
+
+// execCAS executes the lightweight-transaction with serial-consistency
+// from Config, and returns if it was applied.
+func (l *Locker) execCAS(
+	ctx context.Context,
+	stmt string,
+	values ...interface{},
+) (bool, error) {
+	return l.table.Session().
+		Query(stmt, values...).
+		WithContext(ctx).
+		SerialConsistency(l.serialConsistency).
+		MapScanCAS(map[string]interface{}{})
+}
+
+// ttlSeconds returns the TTL in seconds, as used in USING TTL clause.
+func (l *Locker) ttlSeconds() int {
+	return int(l.ttl / time.Second)
+}
+
+// Name returns the lock-name.
+func (lk *Lock) Name() string {
+	return lk.name
+}
+
+// Owner returns the lock-owner.
+func (lk *Lock) Owner() string {
+	return lk.owner
+}
+
+// Lost returns a channel that is closed if the lock is lost, because it
+// was acquired by another owner, or couldn't be renewed before expiring.
+// The owner should stop the work requiring the lock when this is closed.
+func (lk *Lock) Lost() <-chan struct{} {
+	return lk.lost
+}
+
+// Release stops renewing the lock, and releases it. Returns ErrLockNotHeld
+// if the lock isn't held by owner anymore.
+func (lk *Lock) Release(ctx context.Context) error {
+	lk.cancel()
+	<-lk.done
+
+	stmt := fmt.Sprintf(
+		"DELETE FROM %s WHERE name=? IF owner=? ", lk.locker.table.FullName(),
+	)
+	applied, err := lk.locker.execCAS(ctx, stmt, lk.name, lk.owner)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return ErrLockNotHeld
+	}
+	return nil
+}
+
+// renew renews the lock every RenewInterval, until the context is canceled
+// or the lock is lost. The lock is lost if the renewal isn't applied, or if
+// it errors until expiry.
+func (lk *Lock) renew(ctx context.Context, expiry time.Time) {
+	defer close(lk.done)
+
+	l := lk.locker
+	stmt := fmt.Sprintf(
+		"UPDATE %s USING TTL ? SET owner=? WHERE name=? IF owner=? ",
+		l.table.FullName(),
+	)
+	ticker := time.NewTicker(l.renewInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		renewedAt := time.Now()
+		applied, err := l.execCAS(
+			ctx, stmt, l.ttlSeconds(), lk.owner, lk.name, lk.owner,
+		)
+		if err == nil && applied {
+			expiry = renewedAt.Add(l.ttl)
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil || !time.Now().Before(expiry) {
+			close(lk.lost)
+			return
+		}
+	}
+}
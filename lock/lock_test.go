@@ -0,0 +1,174 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	cs "github.com/TerrexTech/go-cassandrautils/cassandra"
+	"github.com/TerrexTech/go-cassandrautils/mocks"
+	"github.com/TerrexTech/go-commonutils/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Locker", func() {
+	var (
+		lock     sync.Mutex
+		stmts    []string
+		values   [][]interface{}
+		session  *mocks.Session
+		keyspace *cs.Keyspace
+	)
+
+	// queries returns the captured queries matching the statement-prefix.
+	queries := func(prefix string) [][]interface{} {
+		lock.Lock()
+		defer lock.Unlock()
+		matching := [][]interface{}{}
+		for i, stmt := range stmts {
+			if strings.HasPrefix(stmt, prefix) {
+				matching = append(matching, values[i])
+			}
+		}
+		return matching
+	}
+
+	BeforeEach(func() {
+		stmts = []string{}
+		values = [][]interface{}{}
+		session = &mocks.Session{
+			MockQuery: func(s string, v ...interface{}) {
+				lock.Lock()
+				defer lock.Unlock()
+				stmts = append(stmts, utils.StandardizeSpaces(s))
+				values = append(values, v)
+			},
+		}
+		var err error
+		keyspace, err = cs.NewKeyspace(session, cs.KeyspaceConfig{
+			Name:        "test",
+			Replication: cs.SimpleStrategy{ReplicationFactor: 1},
+		})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	newLocker := func(c Config) *Locker {
+		c.Keyspace = keyspace
+		l, err := NewLocker(session, c)
+		Expect(err).ToNot(HaveOccurred())
+		return l
+	}
+
+	It("should validate the config", func() {
+		invalid := []Config{
+			Config{Keyspace: keyspace, TTL: time.Millisecond},
+			Config{Keyspace: keyspace, TTL: time.Second, RenewInterval: time.Second},
+			Config{Keyspace: keyspace, RenewInterval: -1},
+		}
+		for _, c := range invalid {
+			_, err := NewLocker(session, c)
+			Expect(err).To(HaveOccurred())
+		}
+
+		l := newLocker(Config{Table: "job_locks"})
+		Expect(l.Table().FullName()).To(Equal("test.job_locks"))
+	})
+
+	It("should acquire, renew and release the lock", func() {
+		l := newLocker(Config{
+			TTL:           time.Second,
+			RenewInterval: 5 * time.Millisecond,
+		})
+
+		lk, err := l.Acquire(context.Background(), "job", "node1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(lk.Name()).To(Equal("job"))
+		Expect(lk.Owner()).To(Equal("node1"))
+		Expect(queries(
+			"INSERT INTO test.locks (name,owner) VALUES (?,?) IF NOT EXISTS USING TTL ?",
+		)).To(Equal([][]interface{}{
+			[]interface{}{"job", "node1", 1},
+		}))
+
+		Eventually(func() int {
+			stmt := "UPDATE test.locks USING TTL ? SET owner=? WHERE name=? IF owner=?"
+			return len(queries(stmt))
+		}).Should(BeNumerically(">=", 2))
+		Expect(queries("UPDATE")[0]).To(Equal([]interface{}{1, "node1", "job", "node1"}))
+
+		Expect(lk.Release(context.Background())).To(Succeed())
+		Expect(queries("DELETE FROM test.locks WHERE name=? IF owner=?")).To(Equal(
+			[][]interface{}{
+				[]interface{}{"job", "node1"},
+			},
+		))
+		Consistently(lk.Lost(), 20*time.Millisecond).ShouldNot(BeClosed())
+	})
+
+	It("should return ErrLockHeld if lock is held by another owner", func() {
+		l := newLocker(Config{})
+		session.MockQueryMapScanCAS = func(dest map[string]interface{}) (bool, error) {
+			dest["owner"] = "node2"
+			return false, nil
+		}
+		_, err := l.Acquire(context.Background(), "job", "node1")
+		Expect(err).To(Equal(ErrLockHeld))
+
+		session.MockQueryMapScanCAS = func(dest map[string]interface{}) (bool, error) {
+			return false, errors.New("timeout")
+		}
+		_, err = l.Acquire(context.Background(), "job", "node1")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should notify if lock is lost", func() {
+		l := newLocker(Config{
+			TTL:           time.Second,
+			RenewInterval: 5 * time.Millisecond,
+		})
+		// Only the acquiring query is applied
+		calls := 0
+		session.MockQueryMapScanCAS = func(dest map[string]interface{}) (bool, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			calls++
+			return calls == 1, nil
+		}
+
+		lk, err := l.Acquire(context.Background(), "job", "node1")
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(lk.Lost()).Should(BeClosed())
+		Expect(lk.Release(context.Background())).To(Equal(ErrLockNotHeld))
+	})
+
+	It("should expire the lock by the TTL in database", func() {
+		l := newLocker(Config{
+			TTL:           1500 * time.Millisecond,
+			RenewInterval: 5 * time.Millisecond,
+		})
+		Expect(l.ttl).To(Equal(time.Second))
+
+		// The acquiring query is slow, and renewals fail
+		calls := 0
+		session.MockQueryMapScanCAS = func(dest map[string]interface{}) (bool, error) {
+			lock.Lock()
+			calls++
+			acquiring := calls == 1
+			lock.Unlock()
+			if acquiring {
+				time.Sleep(200 * time.Millisecond)
+				return true, nil
+			}
+			return false, errors.New("timeout")
+		}
+
+		start := time.Now()
+		lk, err := l.Acquire(context.Background(), "job", "node1")
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(lk.Lost(), 2*time.Second).Should(BeClosed())
+		Expect(time.Since(start)).To(BeNumerically("<", 1200*time.Millisecond))
+	})
+})
@@ -6,9 +6,14 @@ import (
 
 // Iterx mocks the gocqlx Iterx
 type Iterx struct {
-	CqlQuery   driver.QueryI
-	MockClose  func() error
-	MockSelect func(dest interface{}) error
+	CqlQuery       driver.QueryI
+	MockClose      func() error
+	MockGet        func(dest interface{}) error
+	MockMapScan    func(m map[string]interface{}) bool
+	MockPageState  func() []byte
+	MockScan       func(dest ...interface{}) bool
+	MockSelect     func(dest interface{}) error
+	MockStructScan func(dest interface{}) bool
 }
 
 // Close mocks the Iterx#Close function.
@@ -19,6 +24,41 @@ func (i *Iterx) Close() error {
 	return i.MockClose()
 }
 
+// Get mocks the Iterx#Get function.
+func (i *Iterx) Get(dest interface{}) error {
+	if i.MockGet == nil {
+		return nil
+	}
+	return i.MockGet(dest)
+}
+
+// MapScan mocks the Iterx#MapScan function.
+// Returns false (no more rows) if MockMapScan is not set.
+func (i *Iterx) MapScan(m map[string]interface{}) bool {
+	if i.MockMapScan == nil {
+		return false
+	}
+	return i.MockMapScan(m)
+}
+
+// PageState mocks the Iterx#PageState function.
+// Returns nil (no more pages) if MockPageState is not set.
+func (i *Iterx) PageState() []byte {
+	if i.MockPageState == nil {
+		return nil
+	}
+	return i.MockPageState()
+}
+
+// Scan mocks the Iterx#Scan function.
+// Returns false (no more rows) if MockScan is not set.
+func (i *Iterx) Scan(dest ...interface{}) bool {
+	if i.MockScan == nil {
+		return false
+	}
+	return i.MockScan(dest...)
+}
+
 // Select mocks the Iterx#Select function.
 func (i *Iterx) Select(dest interface{}) error {
 	if i.MockSelect == nil {
@@ -26,3 +66,12 @@ func (i *Iterx) Select(dest interface{}) error {
 	}
 	return i.MockSelect(dest)
 }
+
+// StructScan mocks the Iterx#StructScan function.
+// Returns false (no more rows) if MockStructScan is not set.
+func (i *Iterx) StructScan(dest interface{}) bool {
+	if i.MockStructScan == nil {
+		return false
+	}
+	return i.MockStructScan(dest)
+}
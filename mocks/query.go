@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"errors"
 
 	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
@@ -14,9 +15,22 @@ type Query struct {
 	MockExec        func()
 	MockGetPageSize func() uint
 	MockSetPageSize func(size uint)
-	MockRelease     func()
+	MockScanCAS     func(dest ...interface{}) (bool, error)
+	MockMapScanCAS  func(dest map[string]interface{}) (bool, error)
+	// Called by #Consistency
+	MockConsistency func(c cql.Consistency)
+	// Called by #SerialConsistency
+	MockSerialConsistency func(cons cql.SerialConsistency)
+	// Called by #SetPageState
+	MockSetPageState func(state []byte)
+	MockRelease      func()
+	// Called by #WithContext
+	MockWithContext func(ctx context.Context)
+	ctx             context.Context
 	pageSize        uint
+	pageState       []byte
 	statement       string
+	values          []interface{}
 	WrappedQuery    *cql.Query
 }
 
@@ -42,11 +56,56 @@ func (q *Query) Exec() error {
 	return nil
 }
 
+// ScanCAS mocks the lightweight-transaction execution.
+// Returns applied as true if MockScanCAS is not defined.
+func (q *Query) ScanCAS(dest ...interface{}) (bool, error) {
+	if q.ExecError != "" {
+		return false, errors.New(q.ExecError)
+	}
+	if q.MockScanCAS != nil {
+		return q.MockScanCAS(dest...)
+	}
+	return true, nil
+}
+
+// MapScanCAS mocks the lightweight-transaction execution.
+// Returns applied as true if MockMapScanCAS is not defined.
+func (q *Query) MapScanCAS(dest map[string]interface{}) (bool, error) {
+	if q.ExecError != "" {
+		return false, errors.New(q.ExecError)
+	}
+	if q.MockMapScanCAS != nil {
+		return q.MockMapScanCAS(dest)
+	}
+	return true, nil
+}
+
+// Consistency mocks the #Consistency function of driver.Query.
+func (q *Query) Consistency(c cql.Consistency) driver.QueryI {
+	if q.MockConsistency != nil {
+		q.MockConsistency(c)
+	}
+	return q
+}
+
+// SerialConsistency mocks the #SerialConsistency function of driver.Query.
+func (q *Query) SerialConsistency(cons cql.SerialConsistency) driver.QueryI {
+	if q.MockSerialConsistency != nil {
+		q.MockSerialConsistency(cons)
+	}
+	return q
+}
+
 // Statement returns the statement used to create query.
 func (q *Query) Statement() string {
 	return q.statement
 }
 
+// Values returns the bind-values used to create query.
+func (q *Query) Values() []interface{} {
+	return q.values
+}
+
 // GetPageSize mocks fetching the current page-size.
 // Being a mock, this doesn't actually store/track current page style.
 // Use the function #SetPageSize along with this to completely mock page-size.
@@ -71,6 +130,20 @@ func (q *Query) SetPageSize(n uint) driver.QueryI {
 	return q
 }
 
+// GetPageState mocks fetching the paging-state, as set using #SetPageState.
+func (q *Query) GetPageState() []byte {
+	return q.pageState
+}
+
+// SetPageState mocks the #SetPageState function of driver.Query.
+func (q *Query) SetPageState(state []byte) driver.QueryI {
+	q.pageState = state
+	if q.MockSetPageState != nil {
+		q.MockSetPageState(state)
+	}
+	return q
+}
+
 // Release mocks the #Release function driver.Query.
 // It releases the query. Released queries cannot be reused.
 func (q *Query) Release() {
@@ -78,3 +151,17 @@ func (q *Query) Release() {
 		q.MockRelease()
 	}
 }
+
+// Context returns the context, as set using #WithContext.
+func (q *Query) Context() context.Context {
+	return q.ctx
+}
+
+// WithContext mocks the #WithContext function of driver.Query.
+func (q *Query) WithContext(ctx context.Context) driver.QueryI {
+	q.ctx = ctx
+	if q.MockWithContext != nil {
+		q.MockWithContext(ctx)
+	}
+	return q
+}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/TerrexTech/go-cassandrautils/cassandra/driver"
 	cql "github.com/gocql/gocql"
 )
@@ -10,6 +12,14 @@ type Session struct {
 	MockQuery          func(stmt string, values ...interface{})
 	MockQueryExec      func()
 	MockQueryExecError string
+	// Used as Query#MockConsistency for created queries
+	MockQueryConsistency func(c cql.Consistency)
+	// Used as Query#MockMapScanCAS for created queries
+	MockQueryMapScanCAS func(dest map[string]interface{}) (bool, error)
+	// Used as Query#MockSerialConsistency for created queries
+	MockQuerySerialConsistency func(cons cql.SerialConsistency)
+	// Used as Query#MockWithContext for created queries
+	MockQueryWithContext func(ctx context.Context)
 }
 
 // GoCqlSession is a no-op
@@ -24,8 +34,13 @@ func (s *Session) Query(stmt string, values ...interface{}) driver.QueryI {
 		s.MockQuery(stmt, values...)
 	}
 	return &Query{
-		ExecError: s.MockQueryExecError,
-		MockExec:  s.MockQueryExec,
-		statement: stmt,
+		ExecError:             s.MockQueryExecError,
+		MockConsistency:       s.MockQueryConsistency,
+		MockExec:              s.MockQueryExec,
+		MockMapScanCAS:        s.MockQueryMapScanCAS,
+		MockSerialConsistency: s.MockQuerySerialConsistency,
+		MockWithContext:       s.MockQueryWithContext,
+		statement:             stmt,
+		values:                values,
 	}
 }